@@ -0,0 +1,59 @@
+package qb
+
+// aliased is an expression with an optional alias. Subqueries are surrounded
+// with parentheses.
+type aliased struct {
+	expr  Builder
+	alias string
+}
+
+// Table is a raw table name, optionally qualified with a schema. It is written
+// as is, but is validated in strict mode.
+type Table string
+
+// As returns the table with an alias, such as `users AS u`, for use with From
+// or a join.
+func (t Table) As(alias string) Builder { return aliased{identifier(t), alias} }
+
+// Build builds the table name.
+func (t Table) Build() (string, []interface{}, error) { return identifier(t).Build() }
+
+// Col is a raw column name, optionally qualified with a table. It is written
+// as is, but is validated in strict mode.
+type Col string
+
+// As returns the column with an alias, such as `u.name AS name`, for use in a
+// select list.
+func (c Col) As(alias string) Builder { return aliased{identifier(c), alias} }
+
+// Build builds the column name.
+func (c Col) Build() (string, []interface{}, error) { return identifier(c).Build() }
+
+// As returns the query with an alias. In a select list it is written as a
+// scalar subquery, such as `(SELECT COUNT(*) FROM orders) AS order_count`, and
+// in From or a join as a derived table.
+func (q *SelectQuery) As(alias string) Builder { return aliased{q, alias} }
+
+// Build builds the expression followed by `AS alias` if an alias is set.
+func (a aliased) Build() (string, []interface{}, error) { return a.buildDialect(defaultDialect()) }
+
+func (a aliased) buildDialect(d Dialect) (string, []interface{}, error) {
+	if isNil(a.expr) {
+		return "", nil, ErrInvalidType
+	}
+
+	q, p, err := buildWith(a.expr, d)
+	if err != nil {
+		return "", nil, err
+	}
+	if _, ok := a.expr.(*SelectQuery); ok {
+		q = "(" + q + ")"
+	}
+	if a.alias != "" {
+		if err := checkIdents(a.alias); err != nil {
+			return "", nil, err
+		}
+		q += " AS " + a.alias
+	}
+	return q, p, nil
+}
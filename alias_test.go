@@ -0,0 +1,106 @@
+package qb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAlias_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   *SelectQuery
+		want    string
+		want1   []interface{}
+		wantErr bool
+	}{
+		{
+			name:    "Aliased table and columns",
+			query:   Select(Col("u.id"), Col("u.name").As("name")).From(Table("users").As("u")),
+			want:    "SELECT u.id, u.name AS name FROM users AS u",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "FromSub alias",
+			query:   Select("s.id").FromSub(Select("id").From("users").Where(Eq("active", true)), "s"),
+			want:    "SELECT s.id FROM (SELECT id FROM users WHERE active=?) AS s",
+			want1:   []interface{}{true},
+			wantErr: false,
+		},
+		{
+			name:    "FromSub default alias",
+			query:   Select().FromSub(Select("id").From("users"), ""),
+			want:    "SELECT * FROM (SELECT id FROM users) AS from_sub",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "From aliased subquery",
+			query:   Select("s.id").From(Select("id").From("users").As("s")),
+			want:    "SELECT s.id FROM (SELECT id FROM users) AS s",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name: "Join aliased table and subquery",
+			query: Select("u.name", "o.total").
+				From(Table("users").As("u")).
+				InnerJoin(Table("profiles").As("p"), S("p.user_id=u.id")).
+				LeftJoin(Select("user_id", "SUM(total) AS total").From("orders").Where(Gt("total", 0)).GroupBy("user_id").As("o"), S("o.user_id=u.id")).
+				Where(Eq("u.active", true)),
+			want:    "SELECT u.name, o.total FROM users AS u INNER JOIN profiles AS p ON p.user_id=u.id LEFT OUTER JOIN (SELECT user_id, SUM(total) AS total FROM orders WHERE total>? GROUP BY user_id) AS o ON o.user_id=u.id WHERE u.active=?",
+			want1:   []interface{}{0, true},
+			wantErr: false,
+		},
+		{
+			name:    "From subquery default alias",
+			query:   Select().From(Select().From("u")),
+			want:    "SELECT * FROM (SELECT * FROM u) AS from_sub",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Join subquery default alias",
+			query:   Select().From("users").CrossJoin("roles").InnerJoin(Select("user_id").From("orders"), S("join_sub2.user_id=users.id")),
+			want:    "SELECT * FROM users CROSS JOIN roles INNER JOIN (SELECT user_id FROM orders) AS join_sub2 ON join_sub2.user_id=users.id",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Lateral default alias",
+			query:   Select().From("users").CrossJoin(Lateral(Select("id").From("orders").Where(S("orders.user_id=users.id")), "")),
+			want:    "SELECT * FROM users CROSS JOIN LATERAL (SELECT id FROM orders WHERE orders.user_id=users.id) AS lateral_sub",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Nil subquery",
+			query:   Select().From((*SelectQuery)(nil)),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid join table",
+			query:   Select().From("users").InnerJoin(1, S("1=1")),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1, err := tt.query.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SelectQuery.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("SelectQuery.Build() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("SelectQuery.Build() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package qb
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	unionOp     = "UNION"
+	unionAllOp  = "UNION ALL"
+	intersectOp = "INTERSECT"
+	exceptOp    = "EXCEPT"
+)
+
+type compoundPart struct {
+	op    string
+	query *SelectQuery
+}
+
+// compound holds the operands of a compound select. The first operand has no
+// operator.
+type compound struct {
+	parts []compoundPart
+}
+
+// Union combines the queries with the `UNION` operator. The returned query
+// represents the combined result, so ORDER BY, LIMIT, and OFFSET set on it
+// apply to the compound result.
+func Union(queries ...*SelectQuery) *SelectQuery { return combine(unionOp, queries) }
+
+// UnionAll combines the queries with the `UNION ALL` operator.
+func UnionAll(queries ...*SelectQuery) *SelectQuery { return combine(unionAllOp, queries) }
+
+// Intersect combines the queries with the `INTERSECT` operator.
+func Intersect(queries ...*SelectQuery) *SelectQuery { return combine(intersectOp, queries) }
+
+// Except combines the queries with the `EXCEPT` operator.
+func Except(queries ...*SelectQuery) *SelectQuery { return combine(exceptOp, queries) }
+
+func combine(op string, queries []*SelectQuery) *SelectQuery {
+	c := &compound{}
+	for _, q := range queries {
+		c.parts = append(c.parts, compoundPart{op, q})
+	}
+	return &SelectQuery{compound: c}
+}
+
+// Union combines the query with the others using the `UNION` operator. If the
+// query is already a compound query using only `UNION` and without ORDER BY,
+// LIMIT, or OFFSET, the others are appended to it. Otherwise a new compound
+// query is returned with the query as its first operand, so operators always
+// apply from left to right.
+func (q *SelectQuery) Union(queries ...*SelectQuery) *SelectQuery {
+	return q.combine(unionOp, queries)
+}
+
+// UnionAll combines the query with the others using the `UNION ALL` operator.
+func (q *SelectQuery) UnionAll(queries ...*SelectQuery) *SelectQuery {
+	return q.combine(unionAllOp, queries)
+}
+
+// Intersect combines the query with the others using the `INTERSECT` operator.
+func (q *SelectQuery) Intersect(queries ...*SelectQuery) *SelectQuery {
+	return q.combine(intersectOp, queries)
+}
+
+// Except combines the query with the others using the `EXCEPT` operator.
+func (q *SelectQuery) Except(queries ...*SelectQuery) *SelectQuery {
+	return q.combine(exceptOp, queries)
+}
+
+func (q *SelectQuery) combine(op string, queries []*SelectQuery) *SelectQuery {
+	c := q
+	if !q.compound.only(op) || q.hasTail() {
+		c = &SelectQuery{
+			compound: &compound{parts: []compoundPart{{query: q}}},
+			dialect:  q.dialect,
+			rebinder: q.rebinder,
+		}
+	}
+	for _, o := range queries {
+		c.compound.parts = append(c.compound.parts, compoundPart{op, o})
+	}
+	return c
+}
+
+// only reports whether every operator in the compound is op. A nil compound
+// reports false.
+func (c *compound) only(op string) bool {
+	if c == nil {
+		return false
+	}
+	for i, p := range c.parts {
+		if i == 0 {
+			continue
+		}
+		if p.op != op {
+			return false
+		}
+	}
+	return true
+}
+
+// hasTail reports whether the query has clauses that follow its body.
+func (q *SelectQuery) hasTail() bool {
+	return len(q.orderBys) > 0 || q.limit != nil || q.offset != nil
+}
+
+// buildCompound builds a compound query. Each operand is parenthesized if the
+// dialect allows it. Otherwise operands with their own compound, WITH, ORDER
+// BY, or paging clauses are wrapped in a derived table.
+func (q *SelectQuery) buildCompound(d Dialect) (string, []interface{}, error) {
+	if q.table != nil || len(q.cols) > 0 || q.distinct != nil ||
+		len(q.joins) > 0 || len(q.wherePreds) > 0 || len(q.groupBys) > 0 ||
+		len(q.havingPreds) > 0 || len(q.windows) > 0 || q.seek != nil ||
+		len(q.locks) > 0 {
+		return "", nil, ErrInvalidCompound
+	} else if len(q.compound.parts) == 0 {
+		return "", nil, ErrMissingTable
+	}
+
+	var sb strings.Builder
+	var params []interface{}
+
+	if q.with != nil {
+		w, p, err := q.with.buildDialect(d)
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, p...)
+		sb.WriteString(w)
+		sb.WriteString(" ")
+	}
+
+	for i, part := range q.compound.parts {
+		if part.query == nil {
+			return "", nil, ErrMissingTable
+		}
+
+		s, p, err := part.query.buildDialect(d)
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, p...)
+
+		if i > 0 {
+			fmt.Fprintf(&sb, " %s ", part.op)
+		}
+
+		switch {
+		case d.Supports(FeatureParenCompound):
+			fmt.Fprintf(&sb, "(%s)", s)
+		case part.query.compound != nil || part.query.with != nil || part.query.hasTail():
+			fmt.Fprintf(&sb, "SELECT * FROM (%s)", s)
+		default:
+			sb.WriteString(s)
+		}
+	}
+
+	tail, p, err := q.buildTail(d)
+	if err != nil {
+		return "", nil, err
+	}
+	params = append(params, p...)
+	sb.WriteString(tail)
+
+	return sb.String(), params, nil
+}
@@ -0,0 +1,93 @@
+package qb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompound_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   *SelectQuery
+		want    string
+		want1   []interface{}
+		wantErr bool
+	}{
+		{
+			name:    "Union",
+			query:   Union(Select("a").From("t1").Where(Eq("b", 1)), Select("a").From("t2").Where(Eq("b", 2))),
+			want:    "(SELECT a FROM t1 WHERE b=?) UNION (SELECT a FROM t2 WHERE b=?)",
+			want1:   []interface{}{1, 2},
+			wantErr: false,
+		},
+		{
+			name: "Union all with order and limit",
+			query: Select("a").From("t1").Where(Eq("b", 1)).
+				UnionAll(Select("a").From("t2").Where(Eq("b", 2))).
+				UnionAll(Select("a").From("t3")).
+				OrderBy("a", Desc).
+				Limit(5).
+				WithDialect(Postgres),
+			want:    "(SELECT a FROM t1 WHERE b=$1) UNION ALL (SELECT a FROM t2 WHERE b=$2) UNION ALL (SELECT a FROM t3) ORDER BY a DESC LIMIT 5",
+			want1:   []interface{}{1, 2},
+			wantErr: false,
+		},
+		{
+			name:    "Mixed operators apply left to right",
+			query:   Select("a").From("t1").Union(Select("a").From("t2")).Intersect(Select("a").From("t3")),
+			want:    "((SELECT a FROM t1) UNION (SELECT a FROM t2)) INTERSECT (SELECT a FROM t3)",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Except",
+			query:   Except(Select("a").From("t1"), Select("a").From("t2")),
+			want:    "(SELECT a FROM t1) EXCEPT (SELECT a FROM t2)",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "SQLite operands",
+			query:   Union(Select("a").From("t1"), Select("a").From("t2").OrderBy("a", Asc).Limit(1)).WithDialect(SQLite),
+			want:    "SELECT a FROM t1 UNION SELECT * FROM (SELECT a FROM t2 ORDER BY a ASC LIMIT 1)",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Operand with order is not extended",
+			query:   Union(Select("a").From("t1"), Select("a").From("t2")).Limit(1).Union(Select("a").From("t3")),
+			want:    "((SELECT a FROM t1) UNION (SELECT a FROM t2) LIMIT 1) UNION (SELECT a FROM t3)",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Where on compound",
+			query:   Union(Select("a").From("t1"), Select("a").From("t2")).Where(Eq("a", 1)),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "No operands",
+			query:   Union(),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1, err := tt.query.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("compound.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("compound.Build() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("compound.Build() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
@@ -18,19 +18,39 @@ type actionDoNothing string
 const ActionDoNothing = actionDoNothing("NOTHING")
 
 func (c *conflictResolver) Build() (string, []interface{}, error) {
+	return c.buildDialect(defaultDialect())
+}
+
+func (c *conflictResolver) buildDialect(d Dialect) (string, []interface{}, error) {
+	if !d.Supports(FeatureOnConflict) {
+		return "", nil, ErrUnsupported
+	}
+
 	var sb strings.Builder
 	var params []interface{}
 	sb.WriteString("ON CONFLICT ")
 
 	switch v := c.target.(type) {
 	case string:
+		if err := checkTargetCols(v); err != nil {
+			return "", nil, err
+		}
 		fmt.Fprintf(&sb, "(%s)", v)
 	case TargetColumn:
+		if err := checkTargetCols(string(v)); err != nil {
+			return "", nil, err
+		}
 		fmt.Fprintf(&sb, "(%s)", string(v))
 	case TargetConstraint:
+		if err := checkIdents(string(v)); err != nil {
+			return "", nil, err
+		}
 		fmt.Fprintf(&sb, "ON CONSTRAINT %s", string(v))
 	case predicates:
-		q, p, _ := v.Build()
+		q, p, err := v.buildDialect(d)
+		if err != nil {
+			return "", nil, err
+		}
 		fmt.Fprintf(&sb, "WHERE %s", q)
 		params = append(params, p...)
 	default:
@@ -42,8 +62,8 @@ func (c *conflictResolver) Build() (string, []interface{}, error) {
 	switch v := c.action.(type) {
 	case actionDoNothing:
 		sb.WriteString(string(v))
-	case *updateQuery:
-		q, p, err := v.build(false)
+	case *UpdateQuery:
+		q, p, err := v.build(d, false)
 		if err != nil {
 			return "", nil, err
 		}
@@ -55,3 +75,13 @@ func (c *conflictResolver) Build() (string, []interface{}, error) {
 
 	return sb.String(), params, nil
 }
+
+// checkTargetCols checks each column of a comma separated conflict target in
+// strict mode.
+func checkTargetCols(cols string) error {
+	parts := strings.Split(cols, ",")
+	for i, p := range parts {
+		parts[i] = strings.TrimSpace(p)
+	}
+	return checkIdents(parts...)
+}
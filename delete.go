@@ -4,48 +4,226 @@ import (
 	"strings"
 )
 
-type deleteQuery struct {
+// DeleteQuery builds a `DELETE` query. It is created with DeleteFrom.
+type DeleteQuery struct {
+	with       *WithClause
 	table      string
+	quoted     bool
+	targets    []string
+	joins      joins
+	using      []Builder
 	wherePreds predicates
+	orderBys   []orderBy
+	limit      *int
 	returning  []string
+	rebinder   Rebinder
+	dialect    Dialect
+	err        error
 }
 
-func DeleteFrom(table string) *deleteQuery {
-	return &deleteQuery{table: table}
+// DeleteFrom starts a delete query. The table can be a string or a Table,
+// which is written as is, or an Ident, which is quoted for the dialect. Any
+// other type results in ErrInvalidTable.
+func DeleteFrom(table interface{}) *DeleteQuery {
+	q := &DeleteQuery{}
+	switch t := table.(type) {
+	case string:
+		q.table = t
+	case Table:
+		q.table = string(t)
+	case Ident:
+		q.table, q.quoted = string(t), true
+	default:
+		q.setErr(ErrInvalidTable)
+	}
+	return q
+}
+
+// Using adds a table to the `USING` clause, whose columns can be used in the
+// WHERE clause. Like SelectQuery.From, the table can be a string, a Builder,
+// or a *SelectQuery, which is named `using_subN` if it has no alias.
+// `DELETE ... USING` is supported by PostgreSQL.
+func (q *DeleteQuery) Using(table interface{}) *DeleteQuery {
+	t, err := tableBuilder(table, subAlias("using", len(q.using)+1))
+	q.setErr(err)
+	if t != nil {
+		q.using = append(q.using, t)
+	}
+	return q
+}
+
+// InnerJoin joins a table to the table rows are deleted from, as in the MySQL
+// multi-table `DELETE t FROM t INNER JOIN ...`.
+func (q *DeleteQuery) InnerJoin(table interface{}, condition Builder) *DeleteQuery {
+	return q.join(innerJoin, table, condition)
+}
+
+// LeftJoin joins a table with a `LEFT OUTER JOIN`.
+func (q *DeleteQuery) LeftJoin(table interface{}, condition Builder) *DeleteQuery {
+	return q.join(leftOuterJoin, table, condition)
+}
+
+func (q *DeleteQuery) join(joinType joinType, table interface{}, condition Builder) *DeleteQuery {
+	t, err := tableBuilder(table, subAlias("join", len(q.joins)+1))
+	q.setErr(err)
+	q.joins = append(q.joins, newJoin(joinType, t, condition))
+	return q
+}
+
+// Targets sets the tables rows are deleted from when the query has joins. By
+// default, rows are only deleted from the table passed to DeleteFrom.
+func (q *DeleteQuery) Targets(tables ...string) *DeleteQuery {
+	q.targets = append(q.targets, tables...)
+	return q
 }
 
-func (q *deleteQuery) Where(pred Builder) *deleteQuery {
+func (q *DeleteQuery) Where(pred Builder) *DeleteQuery {
 	q.wherePreds = append(q.wherePreds, pred)
 	return q
 }
 
-func (q *deleteQuery) Returning(cols ...string) *deleteQuery {
+// OrderBy appends an expression to the `ORDER BY` clause, which sets the
+// order rows are deleted in when combined with Limit. It is supported by
+// MySQL, and by SQLite when built with SQLITE_ENABLE_UPDATE_DELETE_LIMIT.
+func (q *DeleteQuery) OrderBy(col interface{}, dir OrderDir) *DeleteQuery {
+	exprs, err := toExprs([]interface{}{col})
+	q.setErr(err)
+	if err == nil {
+		q.orderBys = append(q.orderBys, orderBy{exprs[0], dir})
+	}
+	return q
+}
+
+// Limit limits the number of deleted rows, so large tables can be purged in
+// batches. Like OrderBy, it is supported by MySQL and SQLite.
+func (q *DeleteQuery) Limit(l int) *DeleteQuery {
+	q.limit = &l
+	return q
+}
+
+// setErr records the first error encountered while building up the query.
+func (q *DeleteQuery) setErr(err error) {
+	if q.err == nil {
+		q.err = err
+	}
+}
+
+func (q *DeleteQuery) Returning(cols ...string) *DeleteQuery {
 	q.returning = append(q.returning, cols...)
 	return q
 }
 
-func (q *deleteQuery) Build() (string, []interface{}, error) {
-	if q.table == "" {
+func (q *DeleteQuery) RebindWith(r Rebinder) *DeleteQuery {
+	q.rebinder = r
+	return q
+}
+
+// WithDialect sets the dialect used to build the query. If no dialect is set,
+// the package default is used.
+func (q *DeleteQuery) WithDialect(d Dialect) *DeleteQuery {
+	q.dialect = d
+	return q
+}
+
+func (q *DeleteQuery) Build() (string, []interface{}, error) {
+	d := pickDialect(q.dialect)
+	query, params, err := q.buildDialect(d)
+	if err != nil {
+		return "", nil, err
+	}
+	return rebind(query, d, q.rebinder), params, nil
+}
+
+func (q *DeleteQuery) buildDialect(d Dialect) (string, []interface{}, error) {
+	if q.err != nil {
+		return "", nil, q.err
+	} else if q.table == "" {
 		return "", nil, ErrMissingTable
 	}
 
 	var sb strings.Builder
 	var params []interface{}
 
-	sb.WriteString("DELETE FROM ")
-	sb.WriteString(q.table)
+	var tb Builder = identifier(q.table)
+	if q.quoted {
+		tb = Ident(q.table)
+	}
+	table, _, err := buildWith(tb, d)
+	if err != nil {
+		return "", nil, err
+	}
+	if q.with != nil {
+		w, p, err := q.with.buildDialect(d)
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, p...)
+		sb.WriteString(w)
+		sb.WriteString(" ")
+	}
+
+	sb.WriteString("DELETE ")
+	if len(q.joins) > 0 {
+		if !d.Supports(FeatureDeleteJoin) {
+			return "", nil, ErrUnsupported
+		}
+		targets := q.targets
+		if len(targets) == 0 {
+			targets = []string{table}
+		} else if err := checkIdents(targets...); err != nil {
+			return "", nil, err
+		}
+		sb.WriteString(strings.Join(targets, ", "))
+		sb.WriteString(" ")
+	}
+	sb.WriteString("FROM ")
+	sb.WriteString(table)
 
-	if len(q.wherePreds) > 0 {
-		w, p, err := q.wherePreds.Build()
+	if len(q.joins) > 0 {
+		j, p, err := q.joins.buildDialect(d)
 		if err != nil {
 			return "", nil, err
 		}
+		params = append(params, p...)
+		sb.WriteString(" ")
+		sb.WriteString(j)
+	}
+
+	if len(q.using) > 0 {
+		if !d.Supports(FeatureDeleteUsing) {
+			return "", nil, ErrUnsupported
+		}
+		using, p, err := buildExprs(q.using, d)
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, p...)
+		sb.WriteString(" USING ")
+		sb.WriteString(using)
+	}
+
+	where, p, err := q.wherePreds.buildDialect(d)
+	if err != nil {
+		return "", nil, err
+	} else if where != "" {
 		sb.WriteString(" WHERE ")
-		sb.WriteString(w)
+		sb.WriteString(where)
 		params = append(params, p...)
 	}
 
+	tail, p, err := buildModifyTail(q.orderBys, q.limit, len(q.joins) > 0, d)
+	if err != nil {
+		return "", nil, err
+	}
+	params = append(params, p...)
+	sb.WriteString(tail)
+
 	if len(q.returning) > 0 {
+		if !d.Supports(FeatureReturning) {
+			return "", nil, ErrUnsupported
+		} else if err := checkIdents(q.returning...); err != nil {
+			return "", nil, err
+		}
 		sb.WriteString(" RETURNING ")
 		sb.WriteString(strings.Join(q.returning, ", "))
 	}
@@ -53,7 +231,7 @@ func (q *deleteQuery) Build() (string, []interface{}, error) {
 	return sb.String(), params, nil
 }
 
-func (q *deleteQuery) String() string {
+func (q *DeleteQuery) String() string {
 	query, _, _ := q.Build()
 	return query
 }
@@ -8,7 +8,7 @@ import (
 func Test_deleteQuery_String(t *testing.T) {
 	tests := []struct {
 		name    string
-		query   *deleteQuery
+		query   *DeleteQuery
 		want    string
 		want1   []interface{}
 		wantErr bool
@@ -41,19 +41,118 @@ func Test_deleteQuery_String(t *testing.T) {
 			want1:   []interface{}{false},
 			wantErr: false,
 		},
+		{
+			name:    "Rebind with",
+			query:   DeleteFrom("test_table").Where(Eq("a", 1)).Where(Eq("b", 2)).RebindWith(Dollar),
+			want:    "DELETE FROM test_table WHERE a=$1 AND b=$2",
+			want1:   []interface{}{1, 2},
+			wantErr: false,
+		},
+		{
+			name: "Postgres using",
+			query: DeleteFrom("orders").
+				Using(Table("customers").As("c")).
+				Where(S("c.id=orders.customer_id")).
+				Where(Eq("c.closed", true)).
+				Returning("orders.id").
+				WithDialect(Postgres),
+			want:    "DELETE FROM orders USING customers AS c WHERE c.id=orders.customer_id AND c.closed=$1 RETURNING orders.id",
+			want1:   []interface{}{true},
+			wantErr: false,
+		},
+		{
+			name: "MySQL multi-table delete",
+			query: DeleteFrom("orders").
+				InnerJoin("customers", And{S("customers.id=orders.customer_id"), Eq("customers.closed", true)}).
+				Targets("orders", "customers").
+				WithDialect(MySQL),
+			want:    "DELETE orders, customers FROM orders INNER JOIN customers ON (customers.id=orders.customer_id AND customers.closed=?)",
+			want1:   []interface{}{true},
+			wantErr: false,
+		},
+		{
+			name:    "MySQL join default target",
+			query:   DeleteFrom("orders").LeftJoin("items", S("items.order_id=orders.id")).Where(IsNull("items.id")).WithDialect(MySQL),
+			want:    "DELETE orders FROM orders LEFT OUTER JOIN items ON items.order_id=orders.id WHERE items.id IS NULL",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Quoted table",
+			query:   DeleteFrom(Ident("Order Items")).InnerJoin("orders", S("orders.id=order_id")).WithDialect(SQLServer),
+			want:    "DELETE [Order Items] FROM [Order Items] INNER JOIN orders ON orders.id=order_id",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Table type",
+			query:   DeleteFrom(Table("public.events")).Where(Eq("id", 1)),
+			want:    "DELETE FROM public.events WHERE id=?",
+			want1:   []interface{}{1},
+			wantErr: false,
+		},
+		{
+			name:    "Invalid table type",
+			query:   DeleteFrom(1),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Batched delete",
+			query:   DeleteFrom("events").Where(Lt("created_at", "2020-01-01")).OrderBy("id", Asc).Limit(1000).WithDialect(MySQL),
+			want:    "DELETE FROM events WHERE created_at<? ORDER BY id ASC LIMIT 1000",
+			want1:   []interface{}{"2020-01-01"},
+			wantErr: false,
+		},
+		{
+			name:    "Postgres limit unsupported",
+			query:   DeleteFrom("events").Limit(1000).WithDialect(Postgres),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Using subquery default alias",
+			query:   DeleteFrom("a").Using(Select("id").From("b")).Where(S("a.id=using_sub1.id")).WithDialect(Postgres),
+			want:    "DELETE FROM a USING (SELECT id FROM b) AS using_sub1 WHERE a.id=using_sub1.id",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "MySQL using unsupported",
+			query:   DeleteFrom("a").Using("b").WithDialect(MySQL),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Postgres join unsupported",
+			query:   DeleteFrom("a").InnerJoin("b", S("a.id=b.id")).WithDialect(Postgres),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid using table",
+			query:   DeleteFrom("a").Using(1),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, got1, err := tt.query.Build()
 			if (err != nil) != tt.wantErr {
-				t.Errorf("insertQuery.String() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("InsertQuery.String() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if got != tt.want {
-				t.Errorf("insertQuery.String() got = %v, want %v", got, tt.want)
+				t.Errorf("InsertQuery.String() got = %v, want %v", got, tt.want)
 			}
 			if !reflect.DeepEqual(got1, tt.want1) {
-				t.Errorf("insertQuery.String() got1 = %v, want %v", got1, tt.want1)
+				t.Errorf("InsertQuery.String() got1 = %v, want %v", got1, tt.want1)
 			}
 		})
 	}
@@ -0,0 +1,265 @@
+package qb
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// Feature represents an optional piece of SQL syntax that a dialect may or
+// may not support. Features are bit flags and can be combined with `|`.
+type Feature uint64
+
+const (
+	// FeatureDistinctOn allows `SELECT DISTINCT ON (cols)`.
+	FeatureDistinctOn Feature = 1 << iota
+	// FeatureOnConflict allows `INSERT ... ON CONFLICT target action`.
+	FeatureOnConflict
+	// FeatureReturning allows a `RETURNING cols` clause on data modifying
+	// queries.
+	FeatureReturning
+	// FeatureLimitOffset allows paging with `LIMIT n OFFSET m`.
+	FeatureLimitOffset
+	// FeatureOffsetFetch allows paging with
+	// `OFFSET m ROWS FETCH NEXT n ROWS ONLY`, which requires an ORDER BY
+	// clause.
+	FeatureOffsetFetch
+	// FeatureRecursiveKeyword requires the `RECURSIVE` keyword for recursive
+	// common table expressions. Dialects without it omit the keyword.
+	FeatureRecursiveKeyword
+	// FeatureMaterializedCTE allows the `MATERIALIZED` and
+	// `NOT MATERIALIZED` common table expression hints.
+	FeatureMaterializedCTE
+	// FeatureDataModifyingCTE allows insert, update, and delete queries as
+	// common table expressions.
+	FeatureDataModifyingCTE
+	// FeatureParenCompound allows parenthesized operands in compound
+	// queries such as `(SELECT ...) UNION (SELECT ...)`.
+	FeatureParenCompound
+	// FeatureIsDistinctFrom allows the `IS [NOT] DISTINCT FROM` operators.
+	FeatureIsDistinctFrom
+	// FeatureNullSafeEqual allows the MySQL `<=>` operator, which is used in
+	// place of `IS [NOT] DISTINCT FROM` when those are unsupported.
+	FeatureNullSafeEqual
+	// FeatureILike allows the `ILIKE` operator. Dialects without it compare
+	// the lowercased operands with `LIKE`.
+	FeatureILike
+	// FeatureRegexpTilde allows the PostgreSQL `~` and `!~` regular
+	// expression operators.
+	FeatureRegexpTilde
+	// FeatureRegexpKeyword allows the `REGEXP` operator, which is used when
+	// FeatureRegexpTilde is not supported.
+	FeatureRegexpKeyword
+	// FeatureQuantifiedSubquery allows comparing to a subquery with `ANY` or
+	// `ALL`.
+	FeatureQuantifiedSubquery
+	// FeatureArrayQuantifier allows comparing to an array parameter with
+	// `ANY` or `ALL`.
+	FeatureArrayQuantifier
+	// FeatureJoinUsing allows joins with a `USING (cols)` clause.
+	FeatureJoinUsing
+	// FeatureNaturalJoin allows `NATURAL` joins.
+	FeatureNaturalJoin
+	// FeatureLateral allows joining `LATERAL` subqueries.
+	FeatureLateral
+	// FeatureRowValues allows comparing row values, such as
+	// `(a, b) > (?, ?)`.
+	FeatureRowValues
+	// FeatureRowLock allows the `FOR UPDATE` and `FOR SHARE` locking clauses
+	// with `OF`, `NOWAIT`, and `SKIP LOCKED`.
+	FeatureRowLock
+	// FeatureKeyLock allows the `FOR NO KEY UPDATE` and `FOR KEY SHARE`
+	// locking clauses.
+	FeatureKeyLock
+	// FeatureTop allows limiting rows with `SELECT TOP (n)`. It is used when
+	// FeatureLimitOffset is not supported and there is no offset.
+	FeatureTop
+	// FeatureFetchWithTies allows `FETCH FIRST n ROWS WITH TIES`.
+	FeatureFetchWithTies
+	// FeatureUpdateFrom allows `UPDATE ... SET ... FROM tables`.
+	FeatureUpdateFrom
+	// FeatureUpdateJoin allows joining tables to the updated table, as in
+	// `UPDATE a INNER JOIN b ON ... SET ...`.
+	FeatureUpdateJoin
+	// FeatureModifyLimit allows `ORDER BY` and `LIMIT` clauses on update and
+	// delete queries.
+	FeatureModifyLimit
+	// FeatureDeleteUsing allows `DELETE FROM ... USING tables`.
+	FeatureDeleteUsing
+	// FeatureDeleteJoin allows joining tables to the table rows are deleted
+	// from, as in `DELETE t FROM t INNER JOIN ...`.
+	FeatureDeleteJoin
+	// FeatureOffsetRequiresLimit requires a `LIMIT` before every `OFFSET`.
+	// An offset without a limit is written with the largest limit, such as
+	// `LIMIT 18446744073709551615 OFFSET m` for MySQL.
+	FeatureOffsetRequiresLimit
+	// FeatureNegativeLimit treats a negative `LIMIT` as no limit, so an
+	// offset without a limit is written as `LIMIT -1 OFFSET m`, as SQLite
+	// recommends.
+	FeatureNegativeLimit
+	// FeatureUpsertSelectWhere requires a WHERE clause in the select query of
+	// an `INSERT ... SELECT ... ON CONFLICT`, since SQLite otherwise parses
+	// the `ON` as a join condition. `WHERE 1=1` is added when there is none.
+	FeatureUpsertSelectWhere
+)
+
+// requirements are features that restrict the SQL a dialect accepts rather
+// than allow more of it. Generic does not have them.
+const requirements = FeatureOffsetRequiresLimit | FeatureNegativeLimit | FeatureUpsertSelectWhere
+
+// Dialect describes the flavor of SQL generated by a query. A dialect
+// controls how placeholders are rebound, how identifiers are quoted, how
+// paging is written, and which clauses are legal.
+//
+// The dialects Postgres, MySQL, SQLite, and SQLServer are provided. Queries
+// that do not select a dialect use the package default, which is Generic
+// unless changed with SetDefaultDialect.
+type Dialect interface {
+	Rebinder
+
+	// QuoteIdent quotes a single identifier, escaping any quote characters
+	// contained within it.
+	QuoteIdent(string) string
+
+	// Supports reports whether every feature in f is supported.
+	Supports(f Feature) bool
+}
+
+type baseDialect struct {
+	rebinder Rebinder
+	open     string
+	close    string
+	features Feature
+}
+
+func (d baseDialect) Rebind(query string) string {
+	if d.rebinder == nil {
+		return query
+	}
+	return d.rebinder.Rebind(query)
+}
+
+func (d baseDialect) QuoteIdent(ident string) string {
+	return d.open + strings.Replace(ident, d.close, d.close+d.close, -1) + d.close
+}
+
+func (d baseDialect) Supports(f Feature) bool { return d.features&f == f }
+
+var (
+	// Generic leaves `?` placeholders untouched, quotes identifiers with
+	// double quotes, and allows every clause without extra requirements. This matches the output of
+	// queries built without a dialect.
+	Generic Dialect = baseDialect{
+		open:     `"`,
+		close:    `"`,
+		features: ^requirements,
+	}
+
+	// Postgres generates SQL for PostgreSQL using `$1` style placeholders.
+	Postgres Dialect = baseDialect{
+		rebinder: Dollar,
+		open:     `"`,
+		close:    `"`,
+		features: FeatureDistinctOn | FeatureOnConflict | FeatureReturning |
+			FeatureLimitOffset | FeatureOffsetFetch | FeatureRecursiveKeyword |
+			FeatureMaterializedCTE | FeatureDataModifyingCTE | FeatureParenCompound |
+			FeatureIsDistinctFrom | FeatureILike | FeatureRegexpTilde |
+			FeatureQuantifiedSubquery | FeatureArrayQuantifier | FeatureJoinUsing |
+			FeatureNaturalJoin | FeatureLateral | FeatureRowValues | FeatureRowLock |
+			FeatureKeyLock | FeatureFetchWithTies | FeatureUpdateFrom | FeatureDeleteUsing,
+	}
+
+	// MySQL generates SQL for MySQL using `?` placeholders and backtick
+	// quoted identifiers. Backslashes escape characters in string literals,
+	// as in the default MySQL SQL mode.
+	MySQL Dialect = baseDialect{
+		rebinder: Placeholder{Prefix: "?", BackslashEscapes: true},
+		open:     "`",
+		close:    "`",
+		features: FeatureLimitOffset | FeatureRecursiveKeyword | FeatureParenCompound |
+			FeatureNullSafeEqual | FeatureRegexpKeyword | FeatureQuantifiedSubquery |
+			FeatureJoinUsing | FeatureNaturalJoin | FeatureLateral | FeatureRowValues |
+			FeatureRowLock | FeatureUpdateJoin | FeatureModifyLimit | FeatureDeleteJoin |
+			FeatureOffsetRequiresLimit,
+	}
+
+	// SQLite generates SQL for SQLite using `?` placeholders.
+	SQLite Dialect = baseDialect{
+		rebinder: Question,
+		open:     `"`,
+		close:    `"`,
+		features: FeatureOnConflict | FeatureReturning | FeatureLimitOffset |
+			FeatureRecursiveKeyword | FeatureMaterializedCTE | FeatureIsDistinctFrom |
+			FeatureRegexpKeyword | FeatureJoinUsing | FeatureNaturalJoin | FeatureRowValues |
+			FeatureUpdateFrom | FeatureModifyLimit | FeatureOffsetRequiresLimit |
+			FeatureNegativeLimit | FeatureUpsertSelectWhere,
+	}
+
+	// SQLServer generates SQL for Microsoft SQL Server using `@p1` style
+	// placeholders and bracket quoted identifiers.
+	SQLServer Dialect = baseDialect{
+		rebinder: AtP,
+		open:     "[",
+		close:    "]",
+		features: FeatureOffsetFetch | FeatureParenCompound | FeatureIsDistinctFrom |
+			FeatureQuantifiedSubquery | FeatureTop | FeatureUpdateFrom | FeatureDeleteJoin,
+	}
+)
+
+// dialectValue wraps the default dialect so that dialects of different types
+// can be stored in the same atomic.Value.
+type dialectValue struct{ Dialect }
+
+// defaultDialectValue holds the default dialect. It is read by every query
+// that is built, so it is stored atomically.
+var defaultDialectValue atomic.Value
+
+// SetDefaultDialect sets the dialect used by queries that have not selected
+// one with WithDialect. Passing nil restores Generic. It is safe to call while
+// other goroutines build queries, although those queries may use either
+// dialect, so it is best called during initialization.
+func SetDefaultDialect(d Dialect) {
+	if d == nil {
+		d = Generic
+	}
+	defaultDialectValue.Store(dialectValue{d})
+}
+
+// defaultDialect returns the dialect set with SetDefaultDialect, or Generic.
+func defaultDialect() Dialect {
+	if v, ok := defaultDialectValue.Load().(dialectValue); ok {
+		return v.Dialect
+	}
+	return Generic
+}
+
+// dialectBuilder is implemented by builders whose output depends on the
+// dialect. Nested builders are built with the dialect of the outermost query
+// and never rebind their placeholders; that is left to the outermost query.
+type dialectBuilder interface {
+	buildDialect(d Dialect) (string, []interface{}, error)
+}
+
+// buildWith builds b using the dialect d if b supports it.
+func buildWith(b Builder, d Dialect) (string, []interface{}, error) {
+	if db, ok := b.(dialectBuilder); ok {
+		return db.buildDialect(d)
+	}
+	return b.Build()
+}
+
+// pickDialect returns d, or the default dialect when d is nil.
+func pickDialect(d Dialect) Dialect {
+	if d == nil {
+		return defaultDialect()
+	}
+	return d
+}
+
+// rebind rewrites the placeholders of a finished query. An explicit rebinder
+// takes precedence over the dialect.
+func rebind(query string, d Dialect, r Rebinder) string {
+	if r != nil {
+		return r.Rebind(query)
+	}
+	return d.Rebind(query)
+}
@@ -0,0 +1,252 @@
+package qb
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestDialect_QuoteIdent(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		ident   string
+		want    string
+	}{
+		{"Generic", Generic, "a", `"a"`},
+		{"Postgres", Postgres, `a"b`, `"a""b"`},
+		{"MySQL", MySQL, "a`b", "`a``b`"},
+		{"SQLite", SQLite, "a", `"a"`},
+		{"SQLServer", SQLServer, "a]b", "[a]]b]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.dialect.QuoteIdent(tt.ident); got != tt.want {
+				t.Errorf("Dialect.QuoteIdent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDialect_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   Builder
+		want    string
+		want1   []interface{}
+		wantErr bool
+	}{
+		{
+			name:    "Postgres placeholders",
+			query:   Select().From("t").Where(Eq("a", 1)).Where(Pred{"b", " in ", Select("id").From("u").Where(Eq("c", 2))}).WithDialect(Postgres),
+			want:    "SELECT * FROM t WHERE a=$1 AND b in (SELECT id FROM u WHERE c=$2)",
+			want1:   []interface{}{1, 2},
+			wantErr: false,
+		},
+		{
+			name:    "SQL Server placeholders and paging",
+			query:   Select().From("t").Where(Eq("a", 1)).OrderBy("a", Asc).Limit(10).Offset(20).WithDialect(SQLServer),
+			want:    "SELECT * FROM t WHERE a=@p1 ORDER BY a ASC OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY",
+			want1:   []interface{}{1},
+			wantErr: false,
+		},
+		{
+			name:    "MySQL insert quoting",
+			query:   InsertInto("t").Col("a", 1).WithDialect(MySQL),
+			want:    "INSERT INTO `t` (a) VALUES (?)",
+			want1:   []interface{}{1},
+			wantErr: false,
+		},
+		{
+			name:    "Postgres update",
+			query:   Update("t").Set("a", 1).Where(Eq("b", 2)).WithDialect(Postgres),
+			want:    `UPDATE "t" SET a=$1 WHERE b=$2`,
+			want1:   []interface{}{1, 2},
+			wantErr: false,
+		},
+		{
+			name:    "Postgres delete",
+			query:   DeleteFrom("t").Where(Eq("a", 1)).WithDialect(Postgres),
+			want:    "DELETE FROM t WHERE a=$1",
+			want1:   []interface{}{1},
+			wantErr: false,
+		},
+		{
+			name:    "Rebinder overrides dialect",
+			query:   Select().From("t").Where(Eq("a", 1)).WithDialect(Postgres).RebindWith(Colon),
+			want:    "SELECT * FROM t WHERE a=:1",
+			want1:   []interface{}{1},
+			wantErr: false,
+		},
+		{
+			name:    "MySQL null safe equality",
+			query:   Select().From("t").Where(IsDistinctFrom("a", 1)).Where(IsNotDistinctFrom("b", 2)).WithDialect(MySQL),
+			want:    "SELECT * FROM t WHERE NOT (a <=> ?) AND b <=> ?",
+			want1:   []interface{}{1, 2},
+			wantErr: false,
+		},
+		{
+			name:    "MySQL pattern predicates",
+			query:   Select().From("t").Where(ILike("a", "x%")).Where(NotILike("b", "y%")).Where(Regexp("c", "^z")).Where(NotRegexp("d", "^w")).WithDialect(MySQL),
+			want:    "SELECT * FROM t WHERE LOWER(a) LIKE LOWER(?) AND LOWER(b) NOT LIKE LOWER(?) AND c REGEXP ? AND d NOT REGEXP ?",
+			want1:   []interface{}{"x%", "y%", "^z", "^w"},
+			wantErr: false,
+		},
+		{
+			name:    "Postgres pattern predicates",
+			query:   Select().From("t").Where(ILike("a", "x%")).Where(NotRegexp("d", "^w")).WithDialect(Postgres),
+			want:    "SELECT * FROM t WHERE a ILIKE $1 AND d !~ $2",
+			want1:   []interface{}{"x%", "^w"},
+			wantErr: false,
+		},
+		{
+			name:    "Regexp unsupported",
+			query:   Select().From("t").Where(Regexp("a", "x")).WithDialect(SQLServer),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Postgres array quantifier",
+			query:   Select().From("t").Where(Eq("a", 1)).Where(Any("=", "id", []int{1, 2})).WithDialect(Postgres),
+			want:    "SELECT * FROM t WHERE a=$1 AND id = ANY($2)",
+			want1:   []interface{}{1, []int{1, 2}},
+			wantErr: false,
+		},
+		{
+			name:    "MySQL array quantifier unsupported",
+			query:   Select().From("t").Where(All("<>", "id", []int{1, 2})).WithDialect(MySQL),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "SQLite quantified subquery unsupported",
+			query:   Select().From("t").Where(Any("=", "id", Select("id").From("u"))).WithDialect(SQLite),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name: "Postgres lateral join",
+			query: Select("u.id", "o.total").
+				From(Table("users").As("u")).
+				LeftJoin(Lateral(Select("total").From("orders").Where(S("orders.user_id=u.id")).Where(Gt("total", 5)).OrderBy("total", Desc).Limit(3), "o"), S("true")).
+				Where(Eq("u.active", true)).
+				WithDialect(Postgres),
+			want:    "SELECT u.id, o.total FROM users AS u LEFT OUTER JOIN LATERAL (SELECT total FROM orders WHERE orders.user_id=u.id AND total>$1 ORDER BY total DESC LIMIT 3) AS o ON true WHERE u.active=$2",
+			want1:   []interface{}{5, true},
+			wantErr: false,
+		},
+		{
+			name:    "MySQL cross join lateral",
+			query:   Select().From("a").CrossJoin(Lateral(Select("x").From("b").Where(S("b.a_id=a.id")), "l")).WithDialect(MySQL),
+			want:    "SELECT * FROM a CROSS JOIN LATERAL (SELECT x FROM b WHERE b.a_id=a.id) AS l",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "SQLite lateral unsupported",
+			query:   Select().From("a").CrossJoin(Lateral(Select("x").From("b"), "l")).WithDialect(SQLite),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "SQL Server using unsupported",
+			query:   Select().From("a").InnerJoin("b", Using("id")).WithDialect(SQLServer),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "SQL Server natural join unsupported",
+			query:   Select().From("a").NaturalJoin("b").WithDialect(SQLServer),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "MySQL backslash escaped literal",
+			query:   Select(S(`'it\'s ?'`)).From("t").Where(Eq("a", 1)).WithDialect(MySQL).RebindWith(Placeholder{Prefix: "$", Numbered: true, BackslashEscapes: true}),
+			want:    "SELECT 'it\\'s ?' FROM t WHERE a=$1",
+			want1:   []interface{}{1},
+			wantErr: false,
+		},
+		{
+			name:    "DISTINCT ON unsupported",
+			query:   Select().Distinct("a").From("t").WithDialect(MySQL),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "ON CONFLICT unsupported",
+			query:   InsertInto("t").Col("a", 1).OnConflict(TargetColumn("a"), ActionDoNothing).WithDialect(SQLServer),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "RETURNING unsupported",
+			query:   DeleteFrom("t").Returning("id").WithDialect(MySQL),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1, err := tt.query.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Dialect.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Dialect.Build() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("Dialect.Build() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func TestSetDefaultDialect(t *testing.T) {
+	SetDefaultDialect(Postgres)
+	defer SetDefaultDialect(nil)
+
+	got, _, err := Select().From("t").Where(Eq("a", 1)).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT * FROM t WHERE a=$1"; got != want {
+		t.Errorf("Select().Build() got = %v, want %v", got, want)
+	}
+}
+
+func TestSetDefaultDialect_Concurrent(t *testing.T) {
+	defer SetDefaultDialect(nil)
+	defer SetStrictIdents(false)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, _, err := Select("a").From("t").Where(Eq("a", j)).Build(); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		SetDefaultDialect(Postgres)
+		SetStrictIdents(true)
+		SetDefaultDialect(nil)
+		SetStrictIdents(false)
+	}
+	wg.Wait()
+}
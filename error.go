@@ -14,4 +14,11 @@ const (
 	ErrInvalidConflictTarget = Error("invalid conflict target")
 	ErrInvalidConflictAction = Error("invalid conflict action")
 	ErrInvalidType           = Error("invalid type")
+	ErrUnsupported           = Error("not supported by dialect")
+	ErrInvalidIdent          = Error("invalid identifier")
+	ErrParamLimit            = Error("a single row exceeds the parameter limit")
+	ErrInvalidCompound       = Error("compound queries only support WITH, ORDER BY, LIMIT, and OFFSET")
+	ErrMissingJoinCondition  = Error("no join condition specified")
+	ErrInvalidCursor         = Error("invalid cursor")
+	ErrMissingOrder          = Error("no order specified")
 )
@@ -0,0 +1,135 @@
+package qb
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Ident is a possibly qualified SQL identifier, such as `schema.table` or
+// `table.column`. When built, each dot separated part is quoted and escaped
+// according to the dialect. A final part of `*` is left unquoted.
+type Ident string
+
+// Build quotes the identifier using the default dialect.
+func (i Ident) Build() (string, []interface{}, error) { return i.buildDialect(defaultDialect()) }
+
+func (i Ident) buildDialect(d Dialect) (string, []interface{}, error) {
+	parts := strings.Split(string(i), ".")
+	for j, p := range parts {
+		switch {
+		case p == "":
+			return "", nil, ErrInvalidIdent
+		case p == "*" && j == len(parts)-1:
+		default:
+			parts[j] = d.QuoteIdent(p)
+		}
+	}
+	return strings.Join(parts, "."), nil, nil
+}
+
+// strict is 1 when strict identifier mode is enabled. It is read by every
+// query that is built, so it is accessed atomically.
+var strict int32
+
+// SetStrictIdents enables or disables strict identifier mode. In strict mode,
+// raw strings passed as table names, columns, and sort directions must be
+// plain, optionally qualified, identifiers or the query fails to build with
+// ErrInvalidIdent. Expressions must then be passed as a Builder, such as S or
+// Raw, or quoted with Ident. It is safe to call while other goroutines build
+// queries, but it is best called during initialization.
+func SetStrictIdents(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&strict, v)
+}
+
+// strictIdents reports whether strict identifier mode is enabled.
+func strictIdents() bool { return atomic.LoadInt32(&strict) == 1 }
+
+// ValidIdent reports whether s is a plain identifier, optionally qualified
+// with dots, such as `users`, `public.users`, `u.name`, or `u.*`.
+func ValidIdent(s string) bool {
+	if s == "*" {
+		return true
+	}
+
+	parts := strings.Split(s, ".")
+	for i, p := range parts {
+		if p == "*" && i > 0 && i == len(parts)-1 {
+			continue
+		}
+		if !validIdentPart(p) {
+			return false
+		}
+	}
+	return true
+}
+
+func validIdentPart(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		switch {
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		case i > 0 && (c == '$' || c >= '0' && c <= '9'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// checkIdents returns ErrInvalidIdent if strict mode is enabled and any of
+// the raw strings is not a valid identifier.
+func checkIdents(idents ...string) error {
+	if !strictIdents() {
+		return nil
+	}
+	for _, s := range idents {
+		if !ValidIdent(s) {
+			return ErrInvalidIdent
+		}
+	}
+	return nil
+}
+
+// identifier is a raw table or column name. It is written as is, but is
+// validated in strict mode.
+type identifier string
+
+func (i identifier) Build() (string, []interface{}, error) {
+	if err := checkIdents(string(i)); err != nil {
+		return "", nil, err
+	}
+	return string(i), nil, nil
+}
+
+// tableBuilder converts the table argument accepted by the query builders
+// into a Builder. Strings are treated as raw identifiers, and select queries
+// as derived tables. Derived tables must be named, so a select query without
+// an alias is given the alias passed in.
+func tableBuilder(table interface{}, alias string) (Builder, error) {
+	switch v := table.(type) {
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+		return identifier(v), nil
+	case *SelectQuery:
+		if v == nil {
+			return nil, ErrInvalidTable
+		}
+		return v.As(alias), nil
+	case Builder:
+		return v, nil
+	}
+	return nil, ErrInvalidTable
+}
+
+// subAlias returns the default alias of the nth derived table of a clause,
+// such as `join_sub2`.
+func subAlias(prefix string, n int) string { return prefix + "_sub" + strconv.Itoa(n) }
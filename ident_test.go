@@ -0,0 +1,173 @@
+package qb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIdent_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		ident   Ident
+		dialect Dialect
+		want    string
+		wantErr bool
+	}{
+		{"Single part", Ident("users"), Generic, `"users"`, false},
+		{"Qualified", Ident("public.users"), Postgres, `"public"."users"`, false},
+		{"Star", Ident("u.*"), Postgres, `"u".*`, false},
+		{"Embedded quote", Ident(`a"b`), Postgres, `"a""b"`, false},
+		{"MySQL", Ident("db.users"), MySQL, "`db`.`users`", false},
+		{"SQL Server", Ident("dbo.users"), SQLServer, "[dbo].[users]", false},
+		{"Empty part", Ident("a..b"), Generic, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := tt.ident.buildDialect(tt.dialect)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Ident.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Ident.Build() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidIdent(t *testing.T) {
+	tests := []struct {
+		ident string
+		want  bool
+	}{
+		{"users", true},
+		{"public.users", true},
+		{"u.*", true},
+		{"*", true},
+		{"_a1$", true},
+		{"", false},
+		{"1a", false},
+		{"a.", false},
+		{"*.a", false},
+		{"a b", false},
+		{"name; DROP TABLE users", false},
+		{"COUNT(*)", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ident, func(t *testing.T) {
+			if got := ValidIdent(tt.ident); got != tt.want {
+				t.Errorf("ValidIdent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrictIdents(t *testing.T) {
+	SetStrictIdents(true)
+	defer SetStrictIdents(false)
+
+	tests := []struct {
+		name    string
+		query   Builder
+		want    string
+		want1   []interface{}
+		wantErr bool
+	}{
+		{
+			name:    "Valid select",
+			query:   Select("u.id", "u.name").From("public.users").Where(Eq("u.id", 1)).OrderBy("u.name", Desc),
+			want:    "SELECT u.id, u.name FROM public.users WHERE u.id=? ORDER BY u.name DESC",
+			want1:   []interface{}{1},
+			wantErr: false,
+		},
+		{
+			name:    "Invalid table",
+			query:   Select().From("users; DROP TABLE users"),
+			wantErr: true,
+		},
+		{
+			name:    "Invalid column",
+			query:   Select("COUNT(*)").From("users"),
+			wantErr: true,
+		},
+		{
+			name:    "Invalid sort column",
+			query:   Select().From("users").OrderBy("(SELECT 1)", Asc),
+			wantErr: true,
+		},
+		{
+			name:    "Invalid sort direction",
+			query:   Select().From("users").OrderBy("id", OrderDir("ASC; DROP TABLE users")),
+			wantErr: true,
+		},
+		{
+			name:    "Invalid predicate column",
+			query:   DeleteFrom("users").Where(Eq("1=1 OR id", 1)),
+			wantErr: true,
+		},
+		{
+			name:    "Invalid insert column",
+			query:   InsertInto("users").Col("a) VALUES (1); --", 1),
+			wantErr: true,
+		},
+		{
+			name:    "Invalid alias",
+			query:   Select(Col("u.name").As("n FROM secrets --")).From(Table("users").As("u")),
+			wantErr: true,
+		},
+		{
+			name:    "Invalid join table",
+			query:   Select().From("users").InnerJoin("orders; DROP TABLE users", S("1=1")),
+			wantErr: true,
+		},
+		{
+			name:    "Invalid conflict target",
+			query:   InsertInto("users").Col("a", 1).OnConflict("a) DO NOTHING; --", ActionDoNothing),
+			wantErr: true,
+		},
+		{
+			name:    "Invalid conflict column",
+			query:   InsertInto("users").Col("a", 1).OnConflict(TargetColumn("a, (b)"), ActionDoNothing),
+			wantErr: true,
+		},
+		{
+			name:    "Invalid conflict constraint",
+			query:   InsertInto("users").Col("a", 1).OnConflict(TargetConstraint("c DO NOTHING; --"), ActionDoNothing),
+			wantErr: true,
+		},
+		{
+			name:    "Invalid excluded column",
+			query:   InsertInto("users").Col("a", 1).OnConflict("a", Update("").Set("a", Excluded("x; drop"))),
+			wantErr: true,
+		},
+		{
+			name:    "Valid conflict clause",
+			query:   InsertInto("users").Col("a", 1).Col("b", 2).OnConflict(TargetColumn("a, b"), Update("").Set("b", Excluded("b"))),
+			want:    `INSERT INTO "users" (a, b) VALUES (?, ?) ON CONFLICT (a, b) DO UPDATE SET b=EXCLUDED.b`,
+			want1:   []interface{}{1, 2},
+			wantErr: false,
+		},
+		{
+			name:    "Quoted table",
+			query:   Select("id").From(Ident(`weird"name`)),
+			want:    `SELECT id FROM "weird""name"`,
+			want1:   nil,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1, err := tt.query.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Build() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("Build() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
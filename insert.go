@@ -5,25 +5,31 @@ import (
 	"strings"
 )
 
-type insertQuery struct {
+// InsertQuery builds an `INSERT` query. It is created with InsertInto.
+type InsertQuery struct {
+	with      *WithClause
 	table     string
 	valMap    map[string]interface{}
+	cols      []string
+	rows      [][]interface{}
+	source    *SelectQuery
 	returning []string
 	err       error
 	*conflictResolver
 	rebinder Rebinder
+	dialect  Dialect
 }
 
-func InsertInto(table string) *insertQuery {
-	return &insertQuery{table: table, valMap: make(map[string]interface{})}
+func InsertInto(table string) *InsertQuery {
+	return &InsertQuery{table: table, valMap: make(map[string]interface{})}
 }
 
-func (q *insertQuery) Col(col string, val interface{}) *insertQuery {
+func (q *InsertQuery) Col(col string, val interface{}) *InsertQuery {
 	q.valMap[col] = val
 	return q
 }
 
-func (q *insertQuery) Cols(cols []string, vals ...interface{}) *insertQuery {
+func (q *InsertQuery) Cols(cols []string, vals ...interface{}) *InsertQuery {
 	if len(cols) != len(vals) {
 		q.err = ErrColValMismatch
 		return q
@@ -36,56 +42,161 @@ func (q *insertQuery) Cols(cols []string, vals ...interface{}) *insertQuery {
 	return q
 }
 
+// Columns appends columns to the fixed column list used by Values and Rows.
+// Columns cannot be combined with Col or Cols.
+func (q *InsertQuery) Columns(cols ...string) *InsertQuery {
+	q.cols = append(q.cols, cols...)
+	return q
+}
+
+// Values appends a row of values. The values must be in the same order as the
+// columns provided to Columns. If the number of values does not match the
+// number of columns, building the query returns ErrColValMismatch.
+func (q *InsertQuery) Values(vals ...interface{}) *InsertQuery {
+	q.rows = append(q.rows, vals)
+	return q
+}
+
+// Rows appends multiple rows of values. Each row follows the same rules as
+// Values.
+func (q *InsertQuery) Rows(rows ...[]interface{}) *InsertQuery {
+	q.rows = append(q.rows, rows...)
+	return q
+}
+
+// FromSelect inserts the rows returned by a select query instead of a list of
+// values. The columns provided to Columns are matched to the select list in
+// order. If no columns are provided, the column list is omitted.
+func (q *InsertQuery) FromSelect(query *SelectQuery) *InsertQuery {
+	q.source = query
+	return q
+}
+
+// Chunk splits a multi-row insert into queries that each bind at most
+// maxParams parameters, including those of the WITH and conflict clauses. Each
+// query keeps the WITH clause, table, columns, conflict clause, returning
+// columns, and dialect of the original query. ErrParamLimit is returned if a
+// single row does not fit.
+func (q *InsertQuery) Chunk(maxParams int) ([]*InsertQuery, error) {
+	if q.err != nil {
+		return nil, q.err
+	} else if len(q.cols) == 0 || len(q.valMap) > 0 || q.source != nil {
+		return []*InsertQuery{q}, nil
+	}
+
+	d := pickDialect(q.dialect)
+	reserved := 0
+	if q.with != nil {
+		_, p, err := q.with.buildDialect(d)
+		if err != nil {
+			return nil, err
+		}
+		reserved += len(p)
+	}
+	if q.conflictResolver != nil {
+		_, p, err := q.conflictResolver.buildDialect(d)
+		if err != nil {
+			return nil, err
+		}
+		reserved += len(p)
+	}
+
+	perChunk := (maxParams - reserved) / len(q.cols)
+	if perChunk < 1 {
+		return nil, ErrParamLimit
+	} else if len(q.rows) <= perChunk {
+		return []*InsertQuery{q}, nil
+	}
+
+	chunks := make([]*InsertQuery, 0, (len(q.rows)+perChunk-1)/perChunk)
+	for start := 0; start < len(q.rows); start += perChunk {
+		end := start + perChunk
+		if end > len(q.rows) {
+			end = len(q.rows)
+		}
+		c := *q
+		c.rows = q.rows[start:end:end]
+		chunks = append(chunks, &c)
+	}
+	return chunks, nil
+}
+
 // OnConflict adds a `ON CONFLICT target action` clause to the query.
 // This is only for PostgreSQL.
 //
 // The target can take three forms:
-//		1. (column_name) - a column name (TargetColumn)
-//		2. ON CONSTRAINT constraint_name - a UNIQUE constraint name (targetConstraint)
-//		3. WHERE predicate - a where clause (whereClause)
+//  1. (column_name) - a column name (TargetColumn)
+//  2. ON CONSTRAINT constraint_name - a UNIQUE constraint name (targetConstraint)
+//  3. WHERE predicate - a where clause (whereClause)
 //
 // The target can take two forms:
-// 		1. DO NOTHING - nothing is done (ActionDoNothing)
-//		2. DO UPDATE SET col_1=val_1,... WHERE condition - update fields (updateQuery)
-func (q *insertQuery) OnConflict(target, action interface{}) *insertQuery {
+//  1. DO NOTHING - nothing is done (ActionDoNothing)
+//  2. DO UPDATE SET col_1=val_1,... WHERE condition - update fields (UpdateQuery)
+func (q *InsertQuery) OnConflict(target, action interface{}) *InsertQuery {
 	q.conflictResolver = &conflictResolver{target, action}
 	return q
 }
 
-func (q *insertQuery) Returning(cols ...string) *insertQuery {
+func (q *InsertQuery) Returning(cols ...string) *InsertQuery {
 	q.returning = append(q.returning, cols...)
 	return q
 }
 
-func (q *insertQuery) RebindWith(r Rebinder) *insertQuery {
+func (q *InsertQuery) RebindWith(r Rebinder) *InsertQuery {
 	q.rebinder = r
 	return q
 }
 
-func (q *insertQuery) Build() (string, []interface{}, error) {
+// WithDialect sets the dialect used to build the query. If no dialect is set,
+// the package default is used.
+func (q *InsertQuery) WithDialect(d Dialect) *InsertQuery {
+	q.dialect = d
+	return q
+}
+
+func (q *InsertQuery) Build() (string, []interface{}, error) {
+	d := pickDialect(q.dialect)
+	query, params, err := q.buildDialect(d)
+	if err != nil {
+		return "", nil, err
+	}
+	return rebind(query, d, q.rebinder), params, nil
+}
+
+func (q *InsertQuery) buildDialect(d Dialect) (string, []interface{}, error) {
 	if q.table == "" {
 		return "", nil, ErrMissingTable
 	} else if q.err != nil {
 		return "", nil, q.err
 	}
 
-	keys := orderKeys(q.valMap)
-	vals := make([]interface{}, len(keys))
-	for i, k := range keys {
-		vals[i] = q.valMap[k]
+	table, _, err := Ident(q.table).buildDialect(d)
+	if err != nil {
+		return "", nil, err
 	}
 
-	query := fmt.Sprintf(
-		`INSERT INTO "%s" (%s) VALUES %s`,
-		q.table,
-		strings.Join(keys, ", "),
-		GeneratePlaceholders("?", len(vals)),
-	)
+	var query string
+	var params []interface{}
+	if q.source != nil {
+		query, params, err = q.buildSource(d, table)
+	} else {
+		query, params, err = q.buildValues(table)
+	}
+	if err != nil {
+		return "", nil, err
+	}
 
-	params := vals
+	if q.with != nil {
+		w, p, err := q.with.buildDialect(d)
+		if err != nil {
+			return "", nil, err
+		}
+		query = fmt.Sprintf("%s %s", w, query)
+		params = append(p, params...)
+	}
 
 	if q.conflictResolver != nil {
-		cQuery, p, err := q.conflictResolver.Build()
+		cQuery, p, err := q.conflictResolver.buildDialect(d)
 		if err != nil {
 			return "", nil, err
 		}
@@ -94,17 +205,104 @@ func (q *insertQuery) Build() (string, []interface{}, error) {
 	}
 
 	if len(q.returning) > 0 {
+		if !d.Supports(FeatureReturning) {
+			return "", nil, ErrUnsupported
+		} else if err := checkIdents(q.returning...); err != nil {
+			return "", nil, err
+		}
 		query = fmt.Sprintf("%s RETURNING %s", query, strings.Join(q.returning, ", "))
 	}
 
-	if q.rebinder != nil {
-		query = q.rebinder.Rebind(query)
+	return query, params, nil
+}
+
+// buildValues builds the `INSERT INTO ... VALUES` portion of the query.
+func (q *InsertQuery) buildValues(table string) (string, []interface{}, error) {
+	cols, rows, err := q.columnsAndRows()
+	if err != nil {
+		return "", nil, err
+	} else if err := checkIdents(cols...); err != nil {
+		return "", nil, err
+	}
+
+	tuples := make([]string, len(rows))
+	params := make([]interface{}, 0, len(cols)*len(rows))
+	for i, row := range rows {
+		tuples[i] = GeneratePlaceholders("?", len(row))
+		params = append(params, row...)
 	}
 
+	query := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES %s",
+		table,
+		strings.Join(cols, ", "),
+		strings.Join(tuples, ", "),
+	)
 	return query, params, nil
 }
 
-func (q *insertQuery) String() string {
+// buildSource builds the `INSERT INTO ... SELECT` portion of the query.
+func (q *InsertQuery) buildSource(d Dialect, table string) (string, []interface{}, error) {
+	if len(q.valMap) > 0 || len(q.rows) > 0 {
+		return "", nil, ErrColValMismatch
+	} else if err := checkIdents(q.cols...); err != nil {
+		return "", nil, err
+	}
+
+	s, params, err := q.upsertSource(d).buildDialect(d)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(q.cols) == 0 {
+		return fmt.Sprintf("INSERT INTO %s %s", table, s), params, nil
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) %s", table, strings.Join(q.cols, ", "), s), params, nil
+}
+
+// upsertSource returns the select query to insert from. If the dialect needs
+// a WHERE clause before ON CONFLICT and the query has none, a copy of the
+// query with an always true WHERE clause is returned. Compound queries are
+// selected from as a derived table.
+func (q *InsertQuery) upsertSource(d Dialect) *SelectQuery {
+	src := q.source
+	if q.conflictResolver == nil || !d.Supports(FeatureUpsertSelectWhere) {
+		return src
+	} else if src.compound != nil {
+		return Select().From(src.As("insert_sub")).Where(S(sqlTrue))
+	} else if len(src.wherePreds) > 0 || src.seek != nil {
+		return src
+	}
+
+	c := *src
+	c.wherePreds = predicates{S(sqlTrue)}
+	return &c
+}
+
+// columnsAndRows returns the columns and rows to insert. Values set with Col
+// and Cols form a single row ordered by column name.
+func (q *InsertQuery) columnsAndRows() ([]string, [][]interface{}, error) {
+	if len(q.cols) == 0 && len(q.rows) == 0 {
+		keys := orderKeys(q.valMap)
+		vals := make([]interface{}, len(keys))
+		for i, k := range keys {
+			vals[i] = q.valMap[k]
+		}
+		return keys, [][]interface{}{vals}, nil
+	}
+
+	if len(q.valMap) > 0 || len(q.rows) == 0 {
+		return nil, nil, ErrColValMismatch
+	}
+	for _, row := range q.rows {
+		if len(row) != len(q.cols) {
+			return nil, nil, ErrColValMismatch
+		}
+	}
+	return q.cols, q.rows, nil
+}
+
+func (q *InsertQuery) String() string {
 	query, _, _ := q.Build()
 	return query
 }
@@ -8,7 +8,7 @@ import (
 func Test_insertQuery_Build(t *testing.T) {
 	tests := []struct {
 		name    string
-		query   *insertQuery
+		query   *InsertQuery
 		want    string
 		want1   []interface{}
 		wantErr bool
@@ -41,6 +41,93 @@ func Test_insertQuery_Build(t *testing.T) {
 			want1:   []interface{}{"c", "d"},
 			wantErr: false,
 		},
+		{
+			name:    "Multi-row insert",
+			query:   InsertInto("test_table").Columns("b", "a").Values(1, "x").Values(2, "y"),
+			want:    `INSERT INTO "test_table" (b, a) VALUES (?, ?), (?, ?)`,
+			want1:   []interface{}{1, "x", 2, "y"},
+			wantErr: false,
+		},
+		{
+			name:    "Multi-row insert with rows",
+			query:   InsertInto("test_table").Columns("a").Rows([]interface{}{1}, []interface{}{2}).Returning("id"),
+			want:    `INSERT INTO "test_table" (a) VALUES (?), (?) RETURNING id`,
+			want1:   []interface{}{1, 2},
+			wantErr: false,
+		},
+		{
+			name:    "Ragged rows",
+			query:   InsertInto("test_table").Columns("a", "b").Values(1, 2).Values(3),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Columns without values",
+			query:   InsertInto("test_table").Columns("a", "b"),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Mixed Col and Values",
+			query:   InsertInto("test_table").Col("a", 1).Columns("b").Values(2),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Insert from select",
+			query:   InsertInto("test_table").Columns("a", "b").FromSelect(Select("c", "d").From("other_table").Where(Gt("e", 1))),
+			want:    `INSERT INTO "test_table" (a, b) SELECT c, d FROM other_table WHERE e>?`,
+			want1:   []interface{}{1},
+			wantErr: false,
+		},
+		{
+			name: "Insert from select with conflict and returning",
+			query: InsertInto("test_table").
+				FromSelect(Select().From("other_table").Where(Eq("a", 1))).
+				OnConflict(TargetColumn("a"), Update("").Set("b", 2)).
+				Returning("id"),
+			want:    `INSERT INTO "test_table" SELECT * FROM other_table WHERE a=? ON CONFLICT (a) DO UPDATE SET b=? RETURNING id`,
+			want1:   []interface{}{1, 2},
+			wantErr: false,
+		},
+		{
+			name:    "SQLite insert from select with conflict",
+			query:   InsertInto("t").Columns("a").FromSelect(Select("a").From("s")).OnConflict("a", ActionDoNothing).WithDialect(SQLite),
+			want:    `INSERT INTO "t" (a) SELECT a FROM s WHERE 1=1 ON CONFLICT (a) DO NOTHING`,
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "SQLite insert from select with where and conflict",
+			query:   InsertInto("t").Columns("a").FromSelect(Select("a").From("s").Where(Gt("a", 1))).OnConflict("a", ActionDoNothing).WithDialect(SQLite),
+			want:    `INSERT INTO "t" (a) SELECT a FROM s WHERE a>? ON CONFLICT (a) DO NOTHING`,
+			want1:   []interface{}{1},
+			wantErr: false,
+		},
+		{
+			name:    "SQLite insert from compound select with conflict",
+			query:   InsertInto("t").Columns("a").FromSelect(Select("a").From("s").Union(Select("a").From("r"))).OnConflict("a", ActionDoNothing).WithDialect(SQLite),
+			want:    `INSERT INTO "t" (a) SELECT * FROM (SELECT a FROM s UNION SELECT a FROM r) AS insert_sub WHERE 1=1 ON CONFLICT (a) DO NOTHING`,
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Postgres insert from select with conflict",
+			query:   InsertInto("t").Columns("a").FromSelect(Select("a").From("s")).OnConflict("a", ActionDoNothing).WithDialect(Postgres),
+			want:    `INSERT INTO "t" (a) SELECT a FROM s ON CONFLICT (a) DO NOTHING`,
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Insert from select with values",
+			query:   InsertInto("test_table").Col("a", 1).FromSelect(Select().From("other_table")),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
 		{
 			name:    "Missing table",
 			query:   InsertInto("").Col("a", "b"),
@@ -60,15 +147,88 @@ func Test_insertQuery_Build(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			got, got1, err := tt.query.Build()
 			if (err != nil) != tt.wantErr {
-				t.Errorf("insertQuery.String() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("InsertQuery.String() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if got != tt.want {
-				t.Errorf("insertQuery.String() got = %v, want %v", got, tt.want)
+				t.Errorf("InsertQuery.String() got = %v, want %v", got, tt.want)
 			}
 			if !reflect.DeepEqual(got1, tt.want1) {
-				t.Errorf("insertQuery.String() got1 = %v, want %v", got1, tt.want1)
+				t.Errorf("InsertQuery.String() got1 = %v, want %v", got1, tt.want1)
 			}
 		})
 	}
 }
+
+func Test_insertQuery_Chunk(t *testing.T) {
+	q := InsertInto("test_table").Columns("a", "b")
+	for i := 0; i < 5; i++ {
+		q.Values(i, i)
+	}
+
+	chunks, err := q.Chunk(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		`INSERT INTO "test_table" (a, b) VALUES (?, ?), (?, ?)`,
+		`INSERT INTO "test_table" (a, b) VALUES (?, ?), (?, ?)`,
+		`INSERT INTO "test_table" (a, b) VALUES (?, ?)`,
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("InsertQuery.Chunk() got %d chunks, want %d", len(chunks), len(want))
+	}
+
+	var params []interface{}
+	for i, c := range chunks {
+		got, p, err := c.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want[i] {
+			t.Errorf("InsertQuery.Chunk() chunk %d = %v, want %v", i, got, want[i])
+		}
+		params = append(params, p...)
+	}
+	if wantParams := []interface{}{0, 0, 1, 1, 2, 2, 3, 3, 4, 4}; !reflect.DeepEqual(params, wantParams) {
+		t.Errorf("InsertQuery.Chunk() params = %v, want %v", params, wantParams)
+	}
+
+	if _, err := q.Chunk(1); err != ErrParamLimit {
+		t.Errorf("InsertQuery.Chunk() error = %v, want %v", err, ErrParamLimit)
+	}
+}
+
+func Test_insertQuery_ChunkWith(t *testing.T) {
+	q := With("src", Select("id").From("s").Where(Eq("k", "x"))).InsertInto("test_table").Columns("a", "b")
+	for i := 0; i < 3; i++ {
+		q.Values(i, i)
+	}
+
+	chunks, err := q.Chunk(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 3 {
+		t.Fatalf("InsertQuery.Chunk() got %d chunks, want %d", len(chunks), 3)
+	}
+
+	for i, c := range chunks {
+		got, p, err := c.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `WITH src AS (SELECT id FROM s WHERE k=?) INSERT INTO "test_table" (a, b) VALUES (?, ?)`
+		if got != want {
+			t.Errorf("InsertQuery.Chunk() chunk %d = %v, want %v", i, got, want)
+		}
+		if len(p) > 4 {
+			t.Errorf("InsertQuery.Chunk() chunk %d binds %d params, want at most 4", i, len(p))
+		}
+	}
+
+	if _, err := q.Chunk(2); err != ErrParamLimit {
+		t.Errorf("InsertQuery.Chunk() error = %v, want %v", err, ErrParamLimit)
+	}
+}
@@ -33,25 +33,110 @@ func (jt joinType) String() string {
 
 type join struct {
 	joinType  joinType
-	table     string
+	table     Builder
 	condition Builder
+	natural   bool
 }
 
 type joins []join
 
-func newJoin(joinType joinType, table string, condition Builder) join {
-	return join{joinType, table, condition}
+func newJoin(joinType joinType, table Builder, condition Builder) join {
+	return join{joinType: joinType, table: table, condition: condition}
 }
 
-func (jc joins) Build() (string, []interface{}, error) {
+// using is a `USING (cols)` join condition.
+type using []string
+
+// Using returns a join condition that matches rows on the columns with the
+// same names in both tables, such as `USING (id)`.
+func Using(cols ...string) Builder { return using(cols) }
+
+// Build builds the condition as `USING (cols)`.
+func (u using) Build() (string, []interface{}, error) {
+	if len(u) == 0 {
+		return "", nil, ErrMissingJoinCondition
+	} else if err := checkIdents(u...); err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("USING (%s)", strings.Join(u, ", ")), nil, nil
+}
+
+// lateral is a subquery joined with the `LATERAL` keyword.
+type lateral struct {
+	query Builder
+}
+
+// Lateral returns the query as a `LATERAL` derived table with an alias, which
+// can be used as the table of a join. The subquery can reference columns of
+// the tables before it. If the alias is empty, `lateral_sub` is used.
+func Lateral(query *SelectQuery, alias string) Builder {
+	if query == nil {
+		return lateral{}
+	} else if alias == "" {
+		alias = "lateral_sub"
+	}
+	return lateral{query.As(alias)}
+}
+
+// Build builds the table as `LATERAL (query) AS alias`.
+func (l lateral) Build() (string, []interface{}, error) { return l.buildDialect(defaultDialect()) }
+
+func (l lateral) buildDialect(d Dialect) (string, []interface{}, error) {
+	if !d.Supports(FeatureLateral) {
+		return "", nil, ErrUnsupported
+	} else if l.query == nil {
+		return "", nil, ErrInvalidTable
+	}
+
+	q, p, err := buildWith(l.query, d)
+	if err != nil {
+		return "", nil, err
+	}
+	return "LATERAL " + q, p, nil
+}
+
+func (jc joins) Build() (string, []interface{}, error) { return jc.buildDialect(defaultDialect()) }
+
+// buildDialect builds each join. Natural and cross joins are written without a
+// condition, and every other join requires an `ON` condition or Using.
+func (jc joins) buildDialect(d Dialect) (string, []interface{}, error) {
 	parts := make([]string, len(jc))
 	var params []interface{}
 	for i, j := range jc {
-		q, p, err := j.condition.Build()
+		if j.table == nil {
+			return "", nil, ErrMissingTable
+		}
+		t, p, err := buildWith(j.table, d)
 		if err != nil {
 			return "", nil, err
 		}
-		parts[i] = fmt.Sprintf("%s %s ON %s", j.joinType.String(), j.table, q)
+		params = append(params, p...)
+
+		if j.natural {
+			if !d.Supports(FeatureNaturalJoin) {
+				return "", nil, ErrUnsupported
+			}
+			parts[i] = fmt.Sprintf("NATURAL %s %s", j.joinType.String(), t)
+			continue
+		} else if j.joinType == crossJoin {
+			parts[i] = fmt.Sprintf("%s %s", j.joinType.String(), t)
+			continue
+		} else if j.condition == nil {
+			return "", nil, ErrMissingJoinCondition
+		}
+
+		q, p, err := buildWith(j.condition, d)
+		if err != nil {
+			return "", nil, err
+		}
+		if _, ok := j.condition.(using); ok {
+			if !d.Supports(FeatureJoinUsing) {
+				return "", nil, ErrUnsupported
+			}
+			parts[i] = fmt.Sprintf("%s %s %s", j.joinType.String(), t, q)
+		} else {
+			parts[i] = fmt.Sprintf("%s %s ON %s", j.joinType.String(), t, q)
+		}
 		params = append(params, p...)
 	}
 	return strings.Join(parts, " "), params, nil
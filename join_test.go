@@ -15,7 +15,7 @@ func Test_joinClause_String(t *testing.T) {
 	}{
 		{
 			name:    "Single inner join",
-			jc:      joins{join{innerJoin, "b", S("a.id=b.a_id")}},
+			jc:      joins{newJoin(innerJoin, identifier("b"), S("a.id=b.a_id"))},
 			want:    "INNER JOIN b ON a.id=b.a_id",
 			want1:   nil,
 			wantErr: false,
@@ -23,13 +23,55 @@ func Test_joinClause_String(t *testing.T) {
 		{
 			name: "Multiple inner joins",
 			jc: joins{
-				join{innerJoin, "b", S("a.id=b.a_id")},
-				join{innerJoin, "c", S("b.id=c.b_id")},
+				newJoin(innerJoin, identifier("b"), S("a.id=b.a_id")),
+				newJoin(innerJoin, identifier("c"), S("b.id=c.b_id")),
 			},
 			want:    "INNER JOIN b ON a.id=b.a_id INNER JOIN c ON b.id=c.b_id",
 			want1:   nil,
 			wantErr: false,
 		},
+		{
+			name:    "Using",
+			jc:      joins{newJoin(leftOuterJoin, identifier("b"), Using("id", "region"))},
+			want:    "LEFT OUTER JOIN b USING (id, region)",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Empty using",
+			jc:      joins{newJoin(innerJoin, identifier("b"), Using())},
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Cross join",
+			jc:      joins{newJoin(crossJoin, identifier("b"), nil)},
+			want:    "CROSS JOIN b",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Natural join",
+			jc:      joins{{joinType: leftOuterJoin, table: identifier("b"), natural: true}},
+			want:    "NATURAL LEFT OUTER JOIN b",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Missing condition",
+			jc:      joins{newJoin(innerJoin, identifier("b"), nil)},
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Missing table",
+			jc:      joins{newJoin(innerJoin, nil, S("a.id=b.a_id"))},
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
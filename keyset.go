@@ -0,0 +1,194 @@
+package qb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// keyset holds the values of the ORDER BY columns of the row that a page of
+// results starts after or ends before.
+type keyset struct {
+	vals   []interface{}
+	before bool
+}
+
+// After restricts the query to the rows that follow the row with the given
+// values of the ORDER BY columns, in the order of the OrderBy calls. This
+// implements keyset pagination, which stays fast on large tables where OFFSET
+// does not. The ORDER BY columns must not contain NULL values and should end
+// with a unique column so that every row has a distinct position.
+func (q *SelectQuery) After(vals ...interface{}) *SelectQuery {
+	q.seek = &keyset{vals: vals}
+	return q
+}
+
+// Before restricts the query to the rows that precede the row with the given
+// values of the ORDER BY columns. To select the rows closest to that row, the
+// ORDER BY directions are reversed, so the caller must reverse the returned
+// rows to restore the original order.
+func (q *SelectQuery) Before(vals ...interface{}) *SelectQuery {
+	q.seek = &keyset{vals: vals, before: true}
+	return q
+}
+
+// AfterCursor is like After, but takes a cursor created by EncodeCursor. An
+// invalid cursor results in ErrInvalidCursor.
+func (q *SelectQuery) AfterCursor(cursor string) *SelectQuery {
+	vals, err := DecodeCursor(cursor)
+	q.setErr(err)
+	return q.After(vals...)
+}
+
+// BeforeCursor is like Before, but takes a cursor created by EncodeCursor. An
+// invalid cursor results in ErrInvalidCursor.
+func (q *SelectQuery) BeforeCursor(cursor string) *SelectQuery {
+	vals, err := DecodeCursor(cursor)
+	q.setErr(err)
+	return q.Before(vals...)
+}
+
+// orders returns the ordering terms of the query, reversed if it selects the
+// page before a row.
+func (q *SelectQuery) orders() []orderBy {
+	if q.seek == nil || !q.seek.before {
+		return q.orderBys
+	}
+
+	orders := make([]orderBy, len(q.orderBys))
+	for i, o := range q.orderBys {
+		orders[i] = orderBy{o.col, Asc}
+		if !isDesc(o.dir) {
+			orders[i].dir = Desc
+		}
+	}
+	return orders
+}
+
+func isDesc(dir OrderDir) bool {
+	return strings.EqualFold(string(dir), string(Desc))
+}
+
+// seekPred is the predicate comparing the ORDER BY columns to the keyset
+// values.
+type seekPred struct {
+	orders []orderBy
+	vals   []interface{}
+}
+
+// Build builds the predicate using the default dialect.
+func (s seekPred) Build() (string, []interface{}, error) { return s.buildDialect(defaultDialect()) }
+
+// buildDialect builds the predicate. If every column is sorted in the same
+// direction and the dialect supports row values, a single comparison such as
+// `(a, b) > (?, ?)` is used. Otherwise the comparison is expanded into
+// `(a > ? OR (a = ? AND b > ?))`.
+func (s seekPred) buildDialect(d Dialect) (string, []interface{}, error) {
+	if len(s.orders) == 0 || len(s.orders) != len(s.vals) {
+		return "", nil, ErrColValMismatch
+	}
+
+	cols := make([]string, len(s.orders))
+	colParams := make([][]interface{}, len(s.orders))
+	sameDir := true
+	for i, o := range s.orders {
+		q, p, err := buildWith(o.col, d)
+		if err != nil {
+			return "", nil, err
+		}
+		cols[i], colParams[i] = q, p
+		sameDir = sameDir && isDesc(o.dir) == isDesc(s.orders[0].dir)
+	}
+
+	if len(cols) > 1 && sameDir && d.Supports(FeatureRowValues) {
+		var params []interface{}
+		for _, p := range colParams {
+			params = append(params, p...)
+		}
+		params = append(params, s.vals...)
+		return fmt.Sprintf("(%s) %s %s", strings.Join(cols, ", "), seekOp(s.orders[0].dir),
+			GeneratePlaceholders("?", len(cols))), params, nil
+	}
+
+	var ors []string
+	var params []interface{}
+	for i := range cols {
+		terms := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			terms = append(terms, cols[j]+"=?")
+			params = append(params, colParams[j]...)
+			params = append(params, s.vals[j])
+		}
+		terms = append(terms, cols[i]+seekOp(s.orders[i].dir)+"?")
+		params = append(params, colParams[i]...)
+		params = append(params, s.vals[i])
+
+		if len(terms) == 1 {
+			ors = append(ors, terms[0])
+		} else {
+			ors = append(ors, "("+strings.Join(terms, " AND ")+")")
+		}
+	}
+
+	if len(ors) == 1 {
+		return ors[0], params, nil
+	}
+	return "(" + strings.Join(ors, " OR ") + ")", params, nil
+}
+
+// seekOp returns the operator selecting the rows that follow a value in the
+// given direction.
+func seekOp(dir OrderDir) string {
+	if isDesc(dir) {
+		return "<"
+	}
+	return ">"
+}
+
+// EncodeCursor encodes the values of the ORDER BY columns of a row into an
+// opaque token that can be handed to clients and later passed to AfterCursor
+// or BeforeCursor. The values are encoded as JSON, so they must be JSON
+// serializable. Values such as times are decoded as strings, which databases
+// convert back to the column type when comparing.
+func EncodeCursor(vals ...interface{}) (string, error) {
+	b, err := json.Marshal(vals)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecodeCursor decodes a token created by EncodeCursor. Integers are decoded
+// as int64 and other numbers as float64. An invalid token results in
+// ErrInvalidCursor.
+func DecodeCursor(cursor string) ([]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var vals []interface{}
+	if err := dec.Decode(&vals); err != nil || len(vals) == 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	for i, v := range vals {
+		switch n := v.(type) {
+		case json.Number:
+			if iv, err := n.Int64(); err == nil {
+				vals[i] = iv
+			} else if fv, err := n.Float64(); err == nil {
+				vals[i] = fv
+			} else {
+				return nil, ErrInvalidCursor
+			}
+		case []interface{}, map[string]interface{}:
+			return nil, ErrInvalidCursor
+		}
+	}
+	return vals, nil
+}
@@ -0,0 +1,135 @@
+package qb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeyset_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   *SelectQuery
+		want    string
+		want1   []interface{}
+		wantErr bool
+	}{
+		{
+			name:    "After single column",
+			query:   Select().From("posts").Where(Eq("author_id", 7)).OrderBy("id", Asc).After(100).Limit(20),
+			want:    "SELECT * FROM posts WHERE author_id=? AND id>? ORDER BY id ASC LIMIT 20",
+			want1:   []interface{}{7, 100},
+			wantErr: false,
+		},
+		{
+			name:    "After row values",
+			query:   Select().From("posts").OrderBy("created_at", Desc).OrderBy("id", Desc).After("2020-01-01", 100).Limit(20),
+			want:    "SELECT * FROM posts WHERE (created_at, id) < (?, ?) ORDER BY created_at DESC, id DESC LIMIT 20",
+			want1:   []interface{}{"2020-01-01", 100},
+			wantErr: false,
+		},
+		{
+			name:    "After mixed directions",
+			query:   Select().From("posts").OrderBy("score", Desc).OrderBy("id", Asc).After(5, 100),
+			want:    "SELECT * FROM posts WHERE (score<? OR (score=? AND id>?)) ORDER BY score DESC, id ASC",
+			want1:   []interface{}{5, 5, 100},
+			wantErr: false,
+		},
+		{
+			name:    "Before flips directions",
+			query:   Select().From("posts").OrderBy("score", Desc).OrderBy("id", Asc).Before(5, 100).Limit(20),
+			want:    "SELECT * FROM posts WHERE (score>? OR (score=? AND id<?)) ORDER BY score ASC, id DESC LIMIT 20",
+			want1:   []interface{}{5, 5, 100},
+			wantErr: false,
+		},
+		{
+			name:    "Row values unsupported",
+			query:   Select().From("posts").OrderBy("a", Asc).OrderBy("b", Asc).OrderBy("c", Asc).After(1, 2, 3).WithDialect(SQLServer),
+			want:    "SELECT * FROM posts WHERE (a>@p1 OR (a=@p2 AND b>@p3) OR (a=@p4 AND b=@p5 AND c>@p6)) ORDER BY a ASC, b ASC, c ASC",
+			want1:   []interface{}{1, 1, 2, 1, 2, 3},
+			wantErr: false,
+		},
+		{
+			name:    "Parameterized order expression",
+			query:   Select().From("posts").OrderBy(Raw("similarity(title, ?)", []interface{}{"go"}), Desc).OrderBy("id", Asc).After(0.5, 100),
+			want:    "SELECT * FROM posts WHERE (similarity(title, ?)<? OR (similarity(title, ?)=? AND id>?)) ORDER BY similarity(title, ?) DESC, id ASC",
+			want1:   []interface{}{"go", 0.5, "go", 0.5, 100, "go"},
+			wantErr: false,
+		},
+		{
+			name:    "Value count mismatch",
+			query:   Select().From("posts").OrderBy("id", Asc).After(1, 2),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "No order",
+			query:   Select().From("posts").After(1),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid cursor",
+			query:   Select().From("posts").OrderBy("id", Asc).AfterCursor("not a cursor"),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1, err := tt.query.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SelectQuery.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("SelectQuery.Build() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("SelectQuery.Build() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func TestCursor(t *testing.T) {
+	cursor, err := EncodeCursor("2020-01-01T00:00:00Z", 100, 1.5, true, nil)
+	if err != nil {
+		t.Fatalf("EncodeCursor() error = %v", err)
+	}
+
+	got, err := DecodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+	want := []interface{}{"2020-01-01T00:00:00Z", int64(100), 1.5, true, nil}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeCursor() got = %v, want %v", got, want)
+	}
+
+	q, params, err := Select().From("posts").OrderBy("id", Asc).AfterCursor(mustCursor(t, 42)).Build()
+	if err != nil {
+		t.Fatalf("AfterCursor() error = %v", err)
+	} else if q != "SELECT * FROM posts WHERE id>? ORDER BY id ASC" {
+		t.Errorf("AfterCursor() got = %v", q)
+	} else if !reflect.DeepEqual(params, []interface{}{int64(42)}) {
+		t.Errorf("AfterCursor() got1 = %v", params)
+	}
+
+	for _, c := range []string{"", "!!", "e30", "W1td"} {
+		if _, err := DecodeCursor(c); err != ErrInvalidCursor {
+			t.Errorf("DecodeCursor(%q) error = %v, want %v", c, err, ErrInvalidCursor)
+		}
+	}
+}
+
+func mustCursor(t *testing.T, vals ...interface{}) string {
+	t.Helper()
+	c, err := EncodeCursor(vals...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
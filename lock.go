@@ -0,0 +1,91 @@
+package qb
+
+import (
+	"fmt"
+	"strings"
+)
+
+type lockStrength string
+
+const (
+	lockUpdate      lockStrength = "UPDATE"
+	lockNoKeyUpdate lockStrength = "NO KEY UPDATE"
+	lockShare       lockStrength = "SHARE"
+	lockKeyShare    lockStrength = "KEY SHARE"
+)
+
+// lock is a single row locking clause.
+type lock struct {
+	strength lockStrength
+	of       []string
+	wait     string
+}
+
+// ForUpdate adds a `FOR UPDATE` locking clause. The clause can be refined with
+// Of, NoWait, and SkipLocked.
+func (q *SelectQuery) ForUpdate() *SelectQuery { return q.addLock(lockUpdate) }
+
+// ForNoKeyUpdate adds a `FOR NO KEY UPDATE` locking clause. It is only
+// supported by PostgreSQL.
+func (q *SelectQuery) ForNoKeyUpdate() *SelectQuery { return q.addLock(lockNoKeyUpdate) }
+
+// ForShare adds a `FOR SHARE` locking clause.
+func (q *SelectQuery) ForShare() *SelectQuery { return q.addLock(lockShare) }
+
+// ForKeyShare adds a `FOR KEY SHARE` locking clause. It is only supported by
+// PostgreSQL.
+func (q *SelectQuery) ForKeyShare() *SelectQuery { return q.addLock(lockKeyShare) }
+
+func (q *SelectQuery) addLock(strength lockStrength) *SelectQuery {
+	q.locks = append(q.locks, lock{strength: strength})
+	return q
+}
+
+// Of restricts the most recently added locking clause to the given tables.
+func (q *SelectQuery) Of(tables ...string) *SelectQuery {
+	if len(q.locks) > 0 {
+		l := &q.locks[len(q.locks)-1]
+		l.of = append(l.of, tables...)
+	}
+	return q
+}
+
+// NoWait makes the most recently added locking clause fail instead of waiting
+// for locked rows.
+func (q *SelectQuery) NoWait() *SelectQuery { return q.setLockWait("NOWAIT") }
+
+// SkipLocked makes the most recently added locking clause skip locked rows,
+// as used by job queues.
+func (q *SelectQuery) SkipLocked() *SelectQuery { return q.setLockWait("SKIP LOCKED") }
+
+func (q *SelectQuery) setLockWait(wait string) *SelectQuery {
+	if len(q.locks) > 0 {
+		q.locks[len(q.locks)-1].wait = wait
+	}
+	return q
+}
+
+// buildLocks builds the locking clauses, which follow the paging clauses.
+func (q *SelectQuery) buildLocks(d Dialect) (string, error) {
+	var sb strings.Builder
+	for _, l := range q.locks {
+		switch {
+		case !d.Supports(FeatureRowLock):
+			return "", ErrUnsupported
+		case (l.strength == lockNoKeyUpdate || l.strength == lockKeyShare) && !d.Supports(FeatureKeyLock):
+			return "", ErrUnsupported
+		}
+
+		fmt.Fprintf(&sb, " FOR %s", l.strength)
+		if len(l.of) > 0 {
+			if err := checkIdents(l.of...); err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&sb, " OF %s", strings.Join(l.of, ", "))
+		}
+		if l.wait != "" {
+			sb.WriteString(" " + l.wait)
+		}
+	}
+	return sb.String(), nil
+}
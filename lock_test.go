@@ -0,0 +1,81 @@
+package qb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLock_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   *SelectQuery
+		want    string
+		want1   []interface{}
+		wantErr bool
+	}{
+		{
+			name:    "For update skip locked",
+			query:   Select("id").From("jobs").Where(Eq("status", "queued")).OrderBy("id", Asc).Limit(10).ForUpdate().SkipLocked().WithDialect(Postgres),
+			want:    "SELECT id FROM jobs WHERE status=$1 ORDER BY id ASC LIMIT 10 FOR UPDATE SKIP LOCKED",
+			want1:   []interface{}{"queued"},
+			wantErr: false,
+		},
+		{
+			name:    "Multiple clauses with tables",
+			query:   Select().From("a").InnerJoin("b", S("a.id=b.a_id")).ForNoKeyUpdate().Of("a").NoWait().ForKeyShare().Of("b"),
+			want:    "SELECT * FROM a INNER JOIN b ON a.id=b.a_id FOR NO KEY UPDATE OF a NOWAIT FOR KEY SHARE OF b",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "MySQL for share",
+			query:   Select().From("t").ForShare().Of("t").NoWait().WithDialect(MySQL),
+			want:    "SELECT * FROM t FOR SHARE OF t NOWAIT",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "MySQL key lock unsupported",
+			query:   Select().From("t").ForKeyShare().WithDialect(MySQL),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "SQLite unsupported",
+			query:   Select().From("t").ForUpdate().WithDialect(SQLite),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "SQL Server unsupported",
+			query:   Select().From("t").ForUpdate().WithDialect(SQLServer),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Compound unsupported",
+			query:   Union(Select().From("a"), Select().From("b")).ForUpdate(),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1, err := tt.query.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SelectQuery.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("SelectQuery.Build() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("SelectQuery.Build() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
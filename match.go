@@ -0,0 +1,104 @@
+package qb
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+)
+
+// Eqs returns a predicate that requires every column in the map to equal its
+// value. The predicates are combined with `AND` and ordered by column name.
+// Slice values are matched with `IN`, and nil values with `IS NULL`.
+func Eqs(m map[string]interface{}) And {
+	preds := make(And, 0, len(m))
+	for _, k := range orderKeys(m) {
+		preds = append(preds, eqOrIn(k, m[k]))
+	}
+	return preds
+}
+
+// Match returns a predicate built from the fields of a struct, or a pointer to
+// a struct, that have a `db` tag. Every field with a non-zero value becomes an
+// equality predicate, and the predicates are combined with `AND` in field
+// order. Slice values are matched with `IN`.
+//
+// Fields tagged with `db:"-"` or without a `db` tag are ignored. A field tagged
+// with the `zero` option, such as `db:"deleted_at,zero"`, is matched even if it
+// holds the zero value, so a nil pointer becomes `IS NULL`. Fields of embedded
+// structs without a tag are included as if they were fields of the outer
+// struct.
+//
+// If v is not a struct, building the predicate returns ErrInvalidType.
+func Match(v interface{}) Builder {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return errBuilder{ErrInvalidType}
+	}
+
+	preds := And{}
+	matchFields(rv, &preds)
+	return preds
+}
+
+func matchFields(rv reflect.Value, preds *And) {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fv := rv.Field(i)
+
+		tag, ok := f.Tag.Lookup("db")
+		if !ok {
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				matchFields(fv, preds)
+			}
+			continue
+		} else if tag == "-" || f.PkgPath != "" {
+			continue
+		}
+
+		name, opts := tag, ""
+		if j := strings.IndexByte(tag, ','); j >= 0 {
+			name, opts = tag[:j], tag[j+1:]
+		}
+		if name == "" {
+			continue
+		}
+
+		if fv.IsZero() && !hasOption(opts, "zero") {
+			continue
+		}
+		for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+			fv = fv.Elem()
+		}
+		*preds = append(*preds, eqOrIn(name, fv.Interface()))
+	}
+}
+
+func hasOption(opts, opt string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// eqOrIn returns an `IN` predicate for slices and an equality predicate for
+// any other value. Values implementing driver.Valuer, such as array types
+// provided by drivers, are always compared with equality.
+func eqOrIn(col string, val interface{}) Pred {
+	if _, ok := val.(driver.Valuer); !ok && isList(reflect.ValueOf(val)) {
+		return In(col, val)
+	}
+	return Eq(col, val)
+}
+
+// errBuilder is a Builder that always fails with err.
+type errBuilder struct {
+	err error
+}
+
+func (e errBuilder) Build() (string, []interface{}, error) { return "", nil, e.err }
@@ -0,0 +1,87 @@
+package qb
+
+import (
+	"reflect"
+	"testing"
+)
+
+type matchBase struct {
+	TenantID int `db:"tenant_id"`
+}
+
+type matchFilter struct {
+	matchBase
+	Name      string   `db:"name"`
+	Status    []string `db:"status"`
+	Age       *int     `db:"age"`
+	DeletedAt *string  `db:"deleted_at,zero"`
+	Ignored   string   `db:"-"`
+	Untagged  string
+	hidden    string `db:"hidden"`
+}
+
+func TestEqs(t *testing.T) {
+	got, got1, err := Eqs(map[string]interface{}{
+		"b":          2,
+		"a":          "x",
+		"deleted_at": nil,
+		"status":     []string{"new", "open"},
+	}).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "(a=? AND b=? AND deleted_at IS NULL AND status IN (?, ?))"; got != want {
+		t.Errorf("Eqs() got = %v, want %v", got, want)
+	}
+	if want1 := []interface{}{"x", 2, "new", "open"}; !reflect.DeepEqual(got1, want1) {
+		t.Errorf("Eqs() got1 = %v, want %v", got1, want1)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	age := 30
+	tests := []struct {
+		name    string
+		v       interface{}
+		want    string
+		want1   []interface{}
+		wantErr bool
+	}{
+		{
+			name:    "Non-zero fields",
+			v:       matchFilter{matchBase: matchBase{TenantID: 1}, Name: "a", Status: []string{"new", "open"}, Age: &age, Ignored: "x", Untagged: "y", hidden: "z"},
+			want:    "(tenant_id=? AND name=? AND status IN (?, ?) AND age=? AND deleted_at IS NULL)",
+			want1:   []interface{}{1, "a", "new", "open", 30},
+			wantErr: false,
+		},
+		{
+			name:    "Pointer to struct",
+			v:       &matchFilter{Name: "a"},
+			want:    "(name=? AND deleted_at IS NULL)",
+			want1:   []interface{}{"a"},
+			wantErr: false,
+		},
+		{
+			name:    "Not a struct",
+			v:       map[string]interface{}{"a": 1},
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1, err := Match(tt.v).Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Match() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Match() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("Match() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
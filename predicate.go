@@ -2,9 +2,17 @@ package qb
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 )
 
+// sqlTrue and sqlFalse are portable predicates that are always true and
+// always false respectively.
+const (
+	sqlTrue  = "1=1"
+	sqlFalse = "1=0"
+)
+
 // Pred represents a SQL predicate. The convenience methods Eq, Neq, Gt, Gte,
 // Lt, and Lte are provided for composing common predicates.
 type Pred struct {
@@ -13,10 +21,13 @@ type Pred struct {
 	Val interface{}
 }
 
-// Eq returns a predicate using the `=` operator.
+// Eq returns a predicate using the `=` operator. If val is nil or a nil
+// pointer, the predicate is written as `col IS NULL`. A nil Builder, such as a
+// nil *SelectQuery, results in ErrInvalidType.
 func Eq(col string, val interface{}) Pred { return Pred{Col: col, Op: "=", Val: val} }
 
-// Neq returns a predicate using the `!=` operator.
+// Neq returns a predicate using the `!=` operator. If val is nil or a nil
+// pointer, the predicate is written as `col IS NOT NULL`.
 func Neq(col string, val interface{}) Pred { return Pred{Col: col, Op: "!=", Val: val} }
 
 // Gt returns a predicate using the `>` operator.
@@ -31,84 +42,526 @@ func Lt(col string, val interface{}) Pred { return Pred{Col: col, Op: "<", Val:
 // Lte returns a predicate using the `<=` operator.
 func Lte(col string, val interface{}) Pred { return Pred{Col: col, Op: "<=", Val: val} }
 
+// IsNull returns a predicate using the `IS NULL` operator.
+func IsNull(col string) Pred { return Pred{Col: col, Op: " IS ", Val: nil} }
+
+// IsNotNull returns a predicate using the `IS NOT NULL` operator.
+func IsNotNull(col string) Pred { return Pred{Col: col, Op: " IS NOT ", Val: nil} }
+
+// IsDistinctFrom returns a predicate using the `IS DISTINCT FROM` operator.
+// Dialects without the operator, such as MySQL, use `NOT (col <=> ?)`.
+func IsDistinctFrom(col string, val interface{}) Pred {
+	return Pred{Col: col, Op: " IS DISTINCT FROM ", Val: val}
+}
+
+// IsNotDistinctFrom returns a predicate using the `IS NOT DISTINCT FROM`
+// operator. Dialects without the operator, such as MySQL, use `col <=> ?`.
+func IsNotDistinctFrom(col string, val interface{}) Pred {
+	return Pred{Col: col, Op: " IS NOT DISTINCT FROM ", Val: val}
+}
+
+// Like returns a predicate using the `LIKE` operator.
+func Like(col string, pattern interface{}) Pred { return Pred{Col: col, Op: " LIKE ", Val: pattern} }
+
+// NotLike returns a predicate using the `NOT LIKE` operator.
+func NotLike(col string, pattern interface{}) Pred {
+	return Pred{Col: col, Op: " NOT LIKE ", Val: pattern}
+}
+
+// ILike returns a predicate using the case insensitive `ILIKE` operator.
+// Dialects without the operator use `LOWER(col) LIKE LOWER(?)`.
+func ILike(col string, pattern interface{}) Pred { return Pred{Col: col, Op: " ILIKE ", Val: pattern} }
+
+// NotILike returns a predicate using the `NOT ILIKE` operator. Dialects
+// without the operator use `LOWER(col) NOT LIKE LOWER(?)`.
+func NotILike(col string, pattern interface{}) Pred {
+	return Pred{Col: col, Op: " NOT ILIKE ", Val: pattern}
+}
+
+// Regexp returns a predicate matching the column against a regular
+// expression. PostgreSQL uses the `~` operator and MySQL and SQLite use
+// `REGEXP`. SQL Server is not supported.
+func Regexp(col string, pattern interface{}) Pred { return Pred{Col: col, Op: " ~ ", Val: pattern} }
+
+// NotRegexp returns a predicate that is true if the column does not match the
+// regular expression, using `!~` or `NOT REGEXP`.
+func NotRegexp(col string, pattern interface{}) Pred {
+	return Pred{Col: col, Op: " !~ ", Val: pattern}
+}
+
+// In returns a predicate using the `IN` operator. If vals is a slice or array,
+// it is expanded into one placeholder per element. An empty slice results in a
+// predicate that is always false. If vals is a Builder, such as a
+// *SelectQuery, it is used as a subquery.
+func In(col string, vals interface{}) Pred { return Pred{Col: col, Op: " IN ", Val: vals} }
+
+// NotIn returns a predicate using the `NOT IN` operator. It behaves like In,
+// except that an empty slice results in a predicate that is always true.
+func NotIn(col string, vals interface{}) Pred { return Pred{Col: col, Op: " NOT IN ", Val: vals} }
+
 // Or implements the Builder interface for a list of Builders. When built, the
 // slice of builders are combined with the `OR` operator and the entire
-// predicate is surrounded with parentheses.
+// predicate is surrounded with parentheses. An empty Or is always false.
 type Or []Builder
 
 // And implements the Builder interface for a list of Builders. When built, the
 // slice of builders are combined with the `AND` operator and the entire
-// predicate is surrounded with parentheses.
+// predicate is surrounded with parentheses. An empty And is always true.
 type And []Builder
 
 // Build creates a predicate by combining the slice of Builders with the `OR`
-// operator and surrounding the predicate with parentheses.
-func (o Or) Build() (string, []interface{}, error) {
-	parts := make([]string, len(o))
-	params := make([]interface{}, 0, len(o))
+// operator and surrounding the predicate with parentheses. The group is
+// normalized first, so nested groups may be flattened or removed, and a group
+// reduced to a single predicate is not parenthesized.
+func (o Or) Build() (string, []interface{}, error) { return o.buildDialect(defaultDialect()) }
 
-	for i, c := range o {
-		q, p, err := c.Build()
-		if err != nil {
-			return "", nil, err
+func (o Or) buildDialect(d Dialect) (string, []interface{}, error) {
+	return buildGroup(normalize(o), d)
+}
+
+// Build creates a predicate by combining the slice of Builders with the `AND`
+// operator and surrounding the predicate with parentheses. The group is
+// normalized in the same way as Or.
+func (a And) Build() (string, []interface{}, error) { return a.buildDialect(defaultDialect()) }
+
+func (a And) buildDialect(d Dialect) (string, []interface{}, error) {
+	return buildGroup(normalize(a), d)
+}
+
+// normalize returns a predicate equivalent to b in which nested groups using
+// the same operator are flattened, groups that are always true inside an And
+// or always false inside an Or are dropped, and groups with a single predicate
+// are replaced by that predicate. An And containing an always false group
+// becomes Or{}, and an Or containing an always true group becomes And{}.
+func normalize(b Builder) Builder {
+	switch g := b.(type) {
+	case And:
+		return normalizeGroup(g, true)
+	case Or:
+		return normalizeGroup(g, false)
+	}
+	return b
+}
+
+func normalizeGroup(preds []Builder, isAnd bool) Builder {
+	out := make([]Builder, 0, len(preds))
+	for _, c := range preds {
+		switch v := normalize(c).(type) {
+		case And:
+			if !isAnd && len(v) == 0 {
+				return And{}
+			} else if isAnd {
+				out = append(out, v...)
+			} else {
+				out = append(out, v)
+			}
+		case Or:
+			if isAnd && len(v) == 0 {
+				return Or{}
+			} else if !isAnd {
+				out = append(out, v...)
+			} else {
+				out = append(out, v)
+			}
+		default:
+			out = append(out, v)
 		}
-		parts[i] = q
-		params = append(params, p...)
 	}
-	return fmt.Sprintf("(%s)", strings.Join(parts, " OR ")), params, nil
+
+	if len(out) == 1 {
+		return out[0]
+	} else if isAnd {
+		return And(out)
+	}
+	return Or(out)
 }
 
-// Build creates a predicate by combining the slice of Builders with the `AND`
-// operator and surrounding the predicate with parentheses.
-func (a And) Build() (string, []interface{}, error) {
-	parts := make([]string, len(a))
-	params := make([]interface{}, 0, len(a))
+// buildGroup builds a normalized predicate, surrounding it with parentheses if
+// it combines several predicates.
+func buildGroup(b Builder, d Dialect) (string, []interface{}, error) {
+	q, p, err := buildNormalized(b, d)
+	if err != nil {
+		return "", nil, err
+	}
+	if isCompoundGroup(b) {
+		q = "(" + q + ")"
+	}
+	return q, p, nil
+}
 
-	for i, c := range a {
-		q, p, err := c.Build()
+// buildNormalized builds a normalized predicate without surrounding
+// parentheses. Nested groups are parenthesized. An empty And is built as
+// `1=1` and an empty Or as `1=0`.
+func buildNormalized(b Builder, d Dialect) (string, []interface{}, error) {
+	var preds []Builder
+	var sep string
+	switch g := b.(type) {
+	case And:
+		if len(g) == 0 {
+			return sqlTrue, nil, nil
+		}
+		preds, sep = g, " AND "
+	case Or:
+		if len(g) == 0 {
+			return sqlFalse, nil, nil
+		}
+		preds, sep = g, " OR "
+	default:
+		return buildWith(b, d)
+	}
+
+	parts := make([]string, len(preds))
+	params := make([]interface{}, 0, len(preds))
+
+	for i, c := range preds {
+		q, p, err := buildGroup(c, d)
 		if err != nil {
 			return "", nil, err
 		}
 		parts[i] = q
 		params = append(params, p...)
 	}
-	return fmt.Sprintf("(%s)", strings.Join(parts, " AND ")), params, nil
+	return strings.Join(parts, sep), params, nil
+}
+
+// isCompoundGroup reports whether b is an And or Or with more than one
+// predicate.
+func isCompoundGroup(b Builder) bool {
+	switch g := b.(type) {
+	case And:
+		return len(g) > 1
+	case Or:
+		return len(g) > 1
+	}
+	return false
 }
 
 // Build builds a predicate. If the Pred's value implements the Builder
 // interface, then the output of its Build method is used as the predicate's
-// expression. Otherwise, the expression is set to a `?`.
-func (c Pred) Build() (q string, p []interface{}, err error) {
-	switch v := c.Val.(type) {
-	case Builder:
-		q, p, err = v.Build()
-		if err != nil {
-			return "", nil, err
+// expression. If the operator is `IN` or `NOT IN`, a slice value is expanded
+// into a list of `?`. Otherwise, the expression is set to a `?`.
+func (c Pred) Build() (string, []interface{}, error) { return c.buildDialect(defaultDialect()) }
+
+func (c Pred) buildDialect(d Dialect) (q string, p []interface{}, err error) {
+	if err = checkIdents(c.Col); err != nil {
+		return "", nil, err
+	}
+
+	op := strings.ToUpper(strings.TrimSpace(c.Op))
+	if b, ok := c.Val.(Builder); ok && isNil(b) {
+		return "", nil, ErrInvalidType
+	} else if null, ok := nullOps[op]; ok && isNil(c.Val) {
+		return c.Col + null, nil, nil
+	}
+
+	if _, ok := c.Val.(Builder); !ok && (op == "IN" || op == "NOT IN") {
+		q, p = c.buildIn(op == "NOT IN")
+		return q, p, nil
+	}
+
+	rhs, p, err := buildOperand(c.Val, d)
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch op {
+	case "IS DISTINCT FROM", "IS NOT DISTINCT FROM":
+		if d.Supports(FeatureIsDistinctFrom) {
+			break
+		} else if !d.Supports(FeatureNullSafeEqual) {
+			return "", nil, ErrUnsupported
+		}
+		q = fmt.Sprintf("%s <=> %s", c.Col, rhs)
+		if op == "IS DISTINCT FROM" {
+			q = fmt.Sprintf("NOT (%s)", q)
 		}
+		return q, p, nil
+	case "ILIKE", "NOT ILIKE":
+		if d.Supports(FeatureILike) {
+			break
+		}
+		return fmt.Sprintf("LOWER(%s) %s LOWER(%s)", c.Col, strings.Replace(op, "ILIKE", "LIKE", 1), rhs), p, nil
+	case "~", "!~":
+		if d.Supports(FeatureRegexpTilde) {
+			break
+		} else if !d.Supports(FeatureRegexpKeyword) {
+			return "", nil, ErrUnsupported
+		} else if op == "!~" {
+			return fmt.Sprintf("%s NOT REGEXP %s", c.Col, rhs), p, nil
+		}
+		return fmt.Sprintf("%s REGEXP %s", c.Col, rhs), p, nil
+	}
 
-		q = fmt.Sprintf("%s%s(%s)", c.Col, c.Op, q)
-	default:
-		q = fmt.Sprintf("%s%s?", c.Col, c.Op)
-		p = []interface{}{c.Val}
+	return c.Col + c.Op + rhs, p, nil
+}
+
+// buildOperand builds the right hand side of a comparison. Builders are
+// surrounded with parentheses, and any other value is bound as a `?`. A nil
+// Builder, such as a nil *SelectQuery, results in ErrInvalidType.
+func buildOperand(val interface{}, d Dialect) (string, []interface{}, error) {
+	b, ok := val.(Builder)
+	if !ok {
+		return "?", []interface{}{val}, nil
+	} else if isNil(b) {
+		return "", nil, ErrInvalidType
 	}
-	return q, p, nil
+
+	q, p, err := buildWith(b, d)
+	if err != nil {
+		return "", nil, err
+	}
+	return "(" + q + ")", p, nil
 }
 
-type predicates []Builder
+// nullOps maps the operators that are rewritten when comparing to NULL to
+// their replacement.
+var nullOps = map[string]string{
+	"=":      " IS NULL",
+	"IS":     " IS NULL",
+	"!=":     " IS NOT NULL",
+	"<>":     " IS NOT NULL",
+	"IS NOT": " IS NOT NULL",
+}
+
+// isNil reports whether v is nil or a typed nil pointer.
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
 
-func (w predicates) Build() (string, []interface{}, error) {
-	var parts []string
-	var params []interface{}
+// buildIn builds an `IN` or `NOT IN` predicate, expanding slice values into
+// one placeholder per element.
+func (c Pred) buildIn(not bool) (string, []interface{}) {
+	v := reflect.ValueOf(c.Val)
+	if !isList(v) {
+		return fmt.Sprintf("%s%s(?)", c.Col, c.Op), []interface{}{c.Val}
+	}
 
-	for _, c := range w {
-		part, param, err := c.Build()
-		if err != nil {
-			return "", nil, err
+	n := v.Len()
+	if n == 0 {
+		if not {
+			return sqlTrue, nil
 		}
+		return sqlFalse, nil
+	}
+
+	params := make([]interface{}, n)
+	for i := 0; i < n; i++ {
+		params[i] = v.Index(i).Interface()
+	}
+	return fmt.Sprintf("%s%s%s", c.Col, c.Op, GeneratePlaceholders("?", n)), params
+}
+
+// isList reports whether v is a slice or array of anything other than bytes.
+// Byte slices and arrays, such as UUIDs, are treated as a single value.
+func isList(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		return v.Type().Elem().Kind() != reflect.Uint8
+	}
+	return false
+}
+
+// negation negates a predicate.
+type negation struct {
+	pred Builder
+}
+
+// Not returns a predicate that negates pred with the `NOT` operator.
+func Not(pred Builder) Builder { return negation{pred} }
+
+// Build builds the predicate as `NOT (pred)`.
+func (n negation) Build() (string, []interface{}, error) { return n.buildDialect(defaultDialect()) }
+
+func (n negation) buildDialect(d Dialect) (string, []interface{}, error) {
+	if n.pred == nil {
+		return "", nil, ErrInvalidType
+	}
+
+	q, p, err := buildNormalized(normalize(n.pred), d)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("NOT (%s)", q), p, nil
+}
+
+// exists represents an `EXISTS` or `NOT EXISTS` predicate.
+type exists struct {
+	query *SelectQuery
+	not   bool
+}
+
+// Exists returns a predicate that is true if the subquery returns any rows.
+// The subquery may refer to columns of the outer query.
+func Exists(query *SelectQuery) Builder { return exists{query, false} }
+
+// NotExists returns a predicate that is true if the subquery returns no rows.
+func NotExists(query *SelectQuery) Builder { return exists{query, true} }
+
+// Build builds the predicate as `EXISTS (query)`.
+func (e exists) Build() (string, []interface{}, error) { return e.buildDialect(defaultDialect()) }
+
+func (e exists) buildDialect(d Dialect) (string, []interface{}, error) {
+	if e.query == nil {
+		return "", nil, ErrMissingTable
+	}
+
+	q, p, err := e.query.buildDialect(d)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if e.not {
+		return fmt.Sprintf("NOT EXISTS (%s)", q), p, nil
+	}
+	return fmt.Sprintf("EXISTS (%s)", q), p, nil
+}
+
+// quantified represents a comparison with `ANY` or `ALL`.
+type quantified struct {
+	quant string
+	op    string
+	col   string
+	val   interface{}
+}
+
+// Any returns a predicate comparing col to the values of val with op and the
+// `ANY` quantifier, such as `price > ANY(SELECT price FROM offers)`. If val is
+// a Builder, such as a *SelectQuery, it is built as a subquery. Otherwise val
+// is bound as a single array parameter, which is only supported by PostgreSQL,
+// so it usually needs to be wrapped by the driver, as with pq.Array.
+func Any(op, col string, val interface{}) Builder { return quantified{"ANY", op, col, val} }
+
+// All returns a predicate comparing col to the values of val with op and the
+// `ALL` quantifier. val is handled in the same way as by Any.
+func All(op, col string, val interface{}) Builder { return quantified{"ALL", op, col, val} }
+
+// Build builds the predicate as `col op ANY(val)` or `col op ALL(val)`.
+func (c quantified) Build() (string, []interface{}, error) { return c.buildDialect(defaultDialect()) }
+
+func (c quantified) buildDialect(d Dialect) (string, []interface{}, error) {
+	if err := checkIdents(c.col); err != nil {
+		return "", nil, err
+	} else if isNil(c.val) {
+		return "", nil, ErrInvalidType
+	}
+
+	op := strings.TrimSpace(c.op)
+	b, ok := c.val.(Builder)
+	if !ok {
+		if !d.Supports(FeatureArrayQuantifier) {
+			return "", nil, ErrUnsupported
+		}
+		return fmt.Sprintf("%s %s %s(?)", c.col, op, c.quant), []interface{}{c.val}, nil
+	}
+
+	if !d.Supports(FeatureQuantifiedSubquery) {
+		return "", nil, ErrUnsupported
+	}
+	q, p, err := buildWith(b, d)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s %s %s(%s)", c.col, op, c.quant, q), p, nil
+}
+
+// between represents a `BETWEEN` or `NOT BETWEEN` predicate.
+type between struct {
+	col    string
+	lo, hi interface{}
+	not    bool
+}
+
+// Between returns a predicate that is true if the column is within the
+// inclusive range from lo to hi.
+func Between(col string, lo, hi interface{}) Builder { return between{col, lo, hi, false} }
+
+// NotBetween returns a predicate that is true if the column is outside the
+// inclusive range from lo to hi.
+func NotBetween(col string, lo, hi interface{}) Builder { return between{col, lo, hi, true} }
+
+// Build builds the predicate as `col BETWEEN ? AND ?`.
+func (b between) Build() (string, []interface{}, error) { return b.buildDialect(defaultDialect()) }
+
+func (b between) buildDialect(d Dialect) (string, []interface{}, error) {
+	if err := checkIdents(b.col); err != nil {
+		return "", nil, err
+	}
+
+	lo, params, err := buildOperand(b.lo, d)
+	if err != nil {
+		return "", nil, err
+	}
+	hi, p, err := buildOperand(b.hi, d)
+	if err != nil {
+		return "", nil, err
+	}
+	params = append(params, p...)
+
+	op := "BETWEEN"
+	if b.not {
+		op = "NOT BETWEEN"
+	}
+	return fmt.Sprintf("%s %s %s AND %s", b.col, op, lo, hi), params, nil
+}
+
+// likeEscape is the escape character used by EscapeLike. An exclamation mark
+// is used rather than a backslash because it needs no escaping inside MySQL
+// string literals.
+const likeEscape = "!"
+
+var likeReplacer = strings.NewReplacer(
+	likeEscape, likeEscape+likeEscape,
+	"%", likeEscape+"%",
+	"_", likeEscape+"_",
+)
+
+// EscapeLike escapes the `%` and `_` wildcards and the escape character `!`
+// in s so that it matches literally in a `LIKE` pattern with `ESCAPE '!'`.
+// Contains, StartsWith, and EndsWith use it to match user input.
+func EscapeLike(s string) string { return likeReplacer.Replace(s) }
+
+// escapedLike represents a `LIKE` predicate whose pattern was escaped with
+// EscapeLike.
+type escapedLike struct {
+	col     string
+	pattern string
+}
+
+// Contains returns a predicate that is true if the column contains s. The
+// wildcards in s are escaped, so it is safe to use with user input.
+func Contains(col, s string) Builder { return escapedLike{col, "%" + EscapeLike(s) + "%"} }
+
+// StartsWith returns a predicate that is true if the column starts with s.
+// The wildcards in s are escaped.
+func StartsWith(col, s string) Builder { return escapedLike{col, EscapeLike(s) + "%"} }
+
+// EndsWith returns a predicate that is true if the column ends with s. The
+// wildcards in s are escaped.
+func EndsWith(col, s string) Builder { return escapedLike{col, "%" + EscapeLike(s)} }
+
+// Build builds the predicate as `col LIKE ? ESCAPE '!'`.
+func (l escapedLike) Build() (string, []interface{}, error) {
+	if err := checkIdents(l.col); err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s LIKE ? ESCAPE '%s'", l.col, likeEscape), []interface{}{l.pattern}, nil
+}
+
+// predicates holds the conditions of a WHERE or HAVING clause. When built, the
+// conditions are combined with `AND` and normalized, and conditions that are
+// always true build to an empty string so the clause can be omitted.
+type predicates []Builder
 
-		parts = append(parts, part)
-		params = append(params, param...)
+func (w predicates) Build() (string, []interface{}, error) { return w.buildDialect(defaultDialect()) }
 
+func (w predicates) buildDialect(d Dialect) (string, []interface{}, error) {
+	n := normalize(And(w))
+	if g, ok := n.(And); ok && len(g) == 0 {
+		return "", nil, nil
 	}
-	return strings.Join(parts, " AND "), params, nil
+	return buildNormalized(n, d)
 }
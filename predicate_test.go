@@ -0,0 +1,422 @@
+package qb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPred_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		pred    Builder
+		want    string
+		want1   []interface{}
+		wantErr bool
+	}{
+		{
+			name:    "In ints",
+			pred:    In("id", []int{1, 2, 3}),
+			want:    "id IN (?, ?, ?)",
+			want1:   []interface{}{1, 2, 3},
+			wantErr: false,
+		},
+		{
+			name:    "In strings array",
+			pred:    In("name", [2]string{"a", "b"}),
+			want:    "name IN (?, ?)",
+			want1:   []interface{}{"a", "b"},
+			wantErr: false,
+		},
+		{
+			name:    "In single value",
+			pred:    In("id", 1),
+			want:    "id IN (?)",
+			want1:   []interface{}{1},
+			wantErr: false,
+		},
+		{
+			name:    "In byte slice",
+			pred:    In("hash", []byte("ab")),
+			want:    "hash IN (?)",
+			want1:   []interface{}{[]byte("ab")},
+			wantErr: false,
+		},
+		{
+			name:    "Not in",
+			pred:    NotIn("id", []interface{}{1, "a"}),
+			want:    "id NOT IN (?, ?)",
+			want1:   []interface{}{1, "a"},
+			wantErr: false,
+		},
+		{
+			name:    "In empty slice",
+			pred:    In("id", []int{}),
+			want:    "1=0",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Not in empty slice",
+			pred:    NotIn("id", []int(nil)),
+			want:    "1=1",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "In subquery",
+			pred:    In("id", Select("id").From("t").Where(Eq("a", 1))),
+			want:    "id IN (SELECT id FROM t WHERE a=?)",
+			want1:   []interface{}{1},
+			wantErr: false,
+		},
+		{
+			name:    "In nil subquery",
+			pred:    In("id", (*SelectQuery)(nil)),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "NotIn nil subquery",
+			pred:    NotIn("id", (*SelectQuery)(nil)),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Eq nil",
+			pred:    Eq("deleted_at", nil),
+			want:    "deleted_at IS NULL",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Eq typed nil pointer",
+			pred:    Eq("deleted_at", (*string)(nil)),
+			want:    "deleted_at IS NULL",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Eq nil subquery",
+			pred:    Eq("a", (*SelectQuery)(nil)),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Neq nil",
+			pred:    Neq("deleted_at", nil),
+			want:    "deleted_at IS NOT NULL",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Gt nil is not rewritten",
+			pred:    Gt("a", nil),
+			want:    "a>?",
+			want1:   []interface{}{nil},
+			wantErr: false,
+		},
+		{
+			name:    "Is null",
+			pred:    IsNull("a"),
+			want:    "a IS NULL",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Is not null",
+			pred:    IsNotNull("a"),
+			want:    "a IS NOT NULL",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Is distinct from",
+			pred:    IsDistinctFrom("a", 1),
+			want:    "a IS DISTINCT FROM ?",
+			want1:   []interface{}{1},
+			wantErr: false,
+		},
+		{
+			name:    "Is not distinct from",
+			pred:    IsNotDistinctFrom("a", 1),
+			want:    "a IS NOT DISTINCT FROM ?",
+			want1:   []interface{}{1},
+			wantErr: false,
+		},
+		{
+			name:    "Like",
+			pred:    Like("name", "a%"),
+			want:    "name LIKE ?",
+			want1:   []interface{}{"a%"},
+			wantErr: false,
+		},
+		{
+			name:    "Not like",
+			pred:    NotLike("name", "a%"),
+			want:    "name NOT LIKE ?",
+			want1:   []interface{}{"a%"},
+			wantErr: false,
+		},
+		{
+			name:    "ILike",
+			pred:    ILike("name", "a%"),
+			want:    "name ILIKE ?",
+			want1:   []interface{}{"a%"},
+			wantErr: false,
+		},
+		{
+			name:    "Regexp",
+			pred:    Regexp("name", "^a"),
+			want:    "name ~ ?",
+			want1:   []interface{}{"^a"},
+			wantErr: false,
+		},
+		{
+			name:    "Between",
+			pred:    Between("age", 18, 65),
+			want:    "age BETWEEN ? AND ?",
+			want1:   []interface{}{18, 65},
+			wantErr: false,
+		},
+		{
+			name:    "Not between subquery",
+			pred:    NotBetween("age", 18, Select("MAX(age)").From("t")),
+			want:    "age NOT BETWEEN ? AND (SELECT MAX(age) FROM t)",
+			want1:   []interface{}{18},
+			wantErr: false,
+		},
+		{
+			name:    "Contains",
+			pred:    Contains("name", "50%_off!"),
+			want:    "name LIKE ? ESCAPE '!'",
+			want1:   []interface{}{"%50!%!_off!!%"},
+			wantErr: false,
+		},
+		{
+			name:    "Starts with",
+			pred:    StartsWith("name", "a_b"),
+			want:    "name LIKE ? ESCAPE '!'",
+			want1:   []interface{}{"a!_b%"},
+			wantErr: false,
+		},
+		{
+			name:    "Ends with",
+			pred:    EndsWith("name", "a"),
+			want:    "name LIKE ? ESCAPE '!'",
+			want1:   []interface{}{"%a"},
+			wantErr: false,
+		},
+		{
+			name:    "Not",
+			pred:    Not(Eq("a", 1)),
+			want:    "NOT (a=?)",
+			want1:   []interface{}{1},
+			wantErr: false,
+		},
+		{
+			name:    "Not group",
+			pred:    Not(Or{Eq("a", 1), Eq("b", 2)}),
+			want:    "NOT (a=? OR b=?)",
+			want1:   []interface{}{1, 2},
+			wantErr: false,
+		},
+		{
+			name:    "Exists",
+			pred:    Exists(Select("1").From("orders").Where(S("orders.user_id=users.id")).Where(Gt("total", 100))),
+			want:    "EXISTS (SELECT 1 FROM orders WHERE orders.user_id=users.id AND total>?)",
+			want1:   []interface{}{100},
+			wantErr: false,
+		},
+		{
+			name:    "Not exists",
+			pred:    NotExists(Select("1").From("orders").Where(S("orders.user_id=users.id"))),
+			want:    "NOT EXISTS (SELECT 1 FROM orders WHERE orders.user_id=users.id)",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Not nil",
+			pred:    Not(nil),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Any subquery",
+			pred:    Any(">", "price", Select("price").From("offers").Where(Eq("active", true))),
+			want:    "price > ANY(SELECT price FROM offers WHERE active=?)",
+			want1:   []interface{}{true},
+			wantErr: false,
+		},
+		{
+			name:    "All subquery",
+			pred:    All("<=", "price", Select("price").From("offers")),
+			want:    "price <= ALL(SELECT price FROM offers)",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Any array parameter",
+			pred:    Any("=", "id", []int64{1, 2}),
+			want:    "id = ANY(?)",
+			want1:   []interface{}{[]int64{1, 2}},
+			wantErr: false,
+		},
+		{
+			name:    "Any nil",
+			pred:    Any("=", "id", nil),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Lowercase in operator",
+			pred:    Pred{"id", " in ", []int{1, 2}},
+			want:    "id in (?, ?)",
+			want1:   []interface{}{1, 2},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1, err := tt.pred.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Pred.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Pred.Build() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("Pred.Build() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func TestPredicates_Normalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		pred    Builder
+		want    string
+		want1   []interface{}
+		wantErr bool
+	}{
+		{
+			name:    "Empty and",
+			pred:    And{},
+			want:    "1=1",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Empty or",
+			pred:    Or{},
+			want:    "1=0",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Single element group",
+			pred:    And{Eq("a", 1)},
+			want:    "a=?",
+			want1:   []interface{}{1},
+			wantErr: false,
+		},
+		{
+			name:    "Nested and flattened",
+			pred:    And{Eq("a", 1), And{Eq("b", 2), And{Eq("c", 3)}}},
+			want:    "(a=? AND b=? AND c=?)",
+			want1:   []interface{}{1, 2, 3},
+			wantErr: false,
+		},
+		{
+			name:    "Nested or flattened",
+			pred:    Or{Or{Eq("a", 1), Eq("b", 2)}, Eq("c", 3)},
+			want:    "(a=? OR b=? OR c=?)",
+			want1:   []interface{}{1, 2, 3},
+			wantErr: false,
+		},
+		{
+			name:    "Empty groups dropped",
+			pred:    And{And{}, Eq("a", 1), Or{Eq("b", 2), Or{}}},
+			want:    "(a=? AND b=?)",
+			want1:   []interface{}{1, 2},
+			wantErr: false,
+		},
+		{
+			name:    "Mixed groups keep parentheses",
+			pred:    Or{And{Eq("a", 1), Eq("b", 2)}, And{Eq("c", 3)}},
+			want:    "((a=? AND b=?) OR c=?)",
+			want1:   []interface{}{1, 2, 3},
+			wantErr: false,
+		},
+		{
+			name:    "And with false group",
+			pred:    And{Eq("a", 1), Or{}},
+			want:    "1=0",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Or with true group",
+			pred:    Or{Eq("a", 1), And{}},
+			want:    "1=1",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Not empty and",
+			pred:    Not(And{}),
+			want:    "NOT (1=1)",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Not single element group",
+			pred:    Not(Or{And{Eq("a", 1)}}),
+			want:    "NOT (a=?)",
+			want1:   []interface{}{1},
+			wantErr: false,
+		},
+		{
+			name:    "Where with nested groups",
+			pred:    Select("*").From("t").Where(And{Eq("a", 1), And{Eq("b", 2)}}).Where(Or{Eq("c", 3)}),
+			want:    "SELECT * FROM t WHERE a=? AND b=? AND c=?",
+			want1:   []interface{}{1, 2, 3},
+			wantErr: false,
+		},
+		{
+			name:    "Where always true",
+			pred:    Select("*").From("t").Where(And{}).Where(And{Or{And{}}}),
+			want:    "SELECT * FROM t",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Where always false",
+			pred:    DeleteFrom("t").Where(Eq("a", 1)).Where(Or{}),
+			want:    "DELETE FROM t WHERE 1=0",
+			want1:   nil,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1, err := tt.pred.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Build() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("Build() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
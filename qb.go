@@ -1,6 +1,7 @@
 package qb
 
 import (
+	"fmt"
 	"sort"
 	"strings"
 )
@@ -22,6 +23,14 @@ func Raw(q string, p []interface{}) raw { return raw{q: q, p: p} }
 
 func (r raw) Build() (string, []interface{}, error) { return r.q, r.p, nil }
 
+// Bind parameter limits of common databases. These can be passed to the Chunk
+// method of a multi-row insert.
+const (
+	MaxParamsPostgres  = 65535
+	MaxParamsMySQL     = 65535
+	MaxParamsSQLServer = 2100
+)
+
 // Rebinder represents a function that can replace all `?` tokens in the query
 // with dialect specific tokens. These other tokens are dialect and driver
 // specific.
@@ -54,3 +63,62 @@ func orderKeys(m map[string]interface{}) []string {
 	sort.Strings(kArr)
 	return kArr
 }
+
+// toExprs converts a list of strings and Builders into Builders. Strings are
+// treated as raw identifiers, and select queries as scalar subqueries. A
+// []string is expanded into one identifier per element, so that a list of
+// column names can still be passed without converting it. Any other type
+// results in ErrInvalidType.
+func toExprs(vals []interface{}) ([]Builder, error) {
+	exprs := make([]Builder, 0, len(vals))
+	for _, v := range vals {
+		switch e := v.(type) {
+		case string:
+			exprs = append(exprs, identifier(e))
+		case []string:
+			for _, s := range e {
+				exprs = append(exprs, identifier(s))
+			}
+		case *SelectQuery:
+			exprs = append(exprs, aliased{expr: e})
+		case Builder:
+			exprs = append(exprs, e)
+		default:
+			return nil, ErrInvalidType
+		}
+	}
+	return exprs, nil
+}
+
+// buildExprs builds each expression and joins them with commas.
+func buildExprs(exprs []Builder, d Dialect) (string, []interface{}, error) {
+	parts := make([]string, len(exprs))
+	var params []interface{}
+	for i, e := range exprs {
+		q, p, err := buildWith(e, d)
+		if err != nil {
+			return "", nil, err
+		}
+		parts[i] = q
+		params = append(params, p...)
+	}
+	return strings.Join(parts, ", "), params, nil
+}
+
+// buildOrderBys builds a list of ordering terms and joins them with commas.
+func buildOrderBys(orders []orderBy, d Dialect) (string, []interface{}, error) {
+	parts := make([]string, len(orders))
+	var params []interface{}
+	for i, o := range orders {
+		if strictIdents() && o.dir != Asc && o.dir != Desc {
+			return "", nil, ErrInvalidIdent
+		}
+		q, p, err := buildWith(o.col, d)
+		if err != nil {
+			return "", nil, err
+		}
+		parts[i] = fmt.Sprintf("%s %s", q, o.dir)
+		params = append(params, p...)
+	}
+	return strings.Join(parts, ", "), params, nil
+}
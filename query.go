@@ -0,0 +1,135 @@
+package qb
+
+// QueryKind identifies the statement a query builds.
+type QueryKind string
+
+const (
+	KindSelect QueryKind = "SELECT"
+	KindInsert QueryKind = "INSERT"
+	KindUpdate QueryKind = "UPDATE"
+	KindDelete QueryKind = "DELETE"
+)
+
+// Query is implemented by SelectQuery, InsertQuery, UpdateQuery, and
+// DeleteQuery, so queries of any kind can be stored and passed around.
+type Query interface {
+	Builder
+	// Kind returns the statement the query builds.
+	Kind() QueryKind
+	// Tables returns the names of the tables the query reads from or writes
+	// to, without aliases and in the order they appear. Tables of derived
+	// tables and compound operands are included, while tables only used by
+	// subqueries in expressions are not.
+	Tables() []string
+}
+
+var (
+	_ Query = (*SelectQuery)(nil)
+	_ Query = (*InsertQuery)(nil)
+	_ Query = (*UpdateQuery)(nil)
+	_ Query = (*DeleteQuery)(nil)
+)
+
+// Kind returns KindSelect.
+func (q *SelectQuery) Kind() QueryKind { return KindSelect }
+
+// Tables returns the tables of the FROM clause and joins, or of every operand
+// of a compound query.
+func (q *SelectQuery) Tables() []string {
+	var tables []string
+	if q.compound != nil {
+		for _, p := range q.compound.parts {
+			if p.query != nil {
+				tables = appendTables(tables, p.query.Tables()...)
+			}
+		}
+		return tables
+	}
+
+	tables = appendTables(tables, tableNames(q.table)...)
+	return appendJoinTables(tables, q.joins)
+}
+
+// Kind returns KindInsert.
+func (q *InsertQuery) Kind() QueryKind { return KindInsert }
+
+// Tables returns the table inserted into followed by the tables of the
+// source query, if any.
+func (q *InsertQuery) Tables() []string {
+	tables := appendTables(nil, q.table)
+	if q.source != nil {
+		tables = appendTables(tables, q.source.Tables()...)
+	}
+	return tables
+}
+
+// Kind returns KindUpdate.
+func (q *UpdateQuery) Kind() QueryKind { return KindUpdate }
+
+// Tables returns the updated table followed by the joined and FROM tables.
+func (q *UpdateQuery) Tables() []string {
+	tables := appendJoinTables(appendTables(nil, q.table), q.joins)
+	for _, t := range q.from {
+		tables = appendTables(tables, tableNames(t)...)
+	}
+	return tables
+}
+
+// Kind returns KindDelete.
+func (q *DeleteQuery) Kind() QueryKind { return KindDelete }
+
+// Tables returns the table rows are deleted from followed by the joined and
+// USING tables.
+func (q *DeleteQuery) Tables() []string {
+	tables := appendJoinTables(appendTables(nil, q.table), q.joins)
+	for _, t := range q.using {
+		tables = appendTables(tables, tableNames(t)...)
+	}
+	return tables
+}
+
+// tableNames returns the names of the tables referenced by a table Builder.
+// Expressions such as S and Raw have no known tables.
+func tableNames(b Builder) []string {
+	switch v := b.(type) {
+	case identifier:
+		return []string{string(v)}
+	case Table:
+		return []string{string(v)}
+	case Ident:
+		return []string{string(v)}
+	case aliased:
+		return tableNames(v.expr)
+	case lateral:
+		return tableNames(v.query)
+	case *SelectQuery:
+		if v != nil {
+			return v.Tables()
+		}
+	}
+	return nil
+}
+
+func appendJoinTables(tables []string, js joins) []string {
+	for _, j := range js {
+		tables = appendTables(tables, tableNames(j.table)...)
+	}
+	return tables
+}
+
+// appendTables appends the non-empty names that are not already in tables.
+func appendTables(tables []string, names ...string) []string {
+outer:
+	for _, n := range names {
+		if n == "" {
+			continue
+		}
+		for _, t := range tables {
+			if t == n {
+				continue outer
+			}
+		}
+		tables = append(tables, n)
+	}
+	return tables
+}
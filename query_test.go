@@ -0,0 +1,67 @@
+package qb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQuery_Tables(t *testing.T) {
+	tests := []struct {
+		name  string
+		query Query
+		kind  QueryKind
+		want  []string
+	}{
+		{
+			name: "Select with joins",
+			query: Select().
+				From(Table("users").As("u")).
+				InnerJoin("orders", S("orders.user_id=u.id")).
+				LeftJoin(Select("user_id").From("payments").As("p"), S("p.user_id=u.id")).
+				CrossJoin(Lateral(Select("1").From("events"), "e")).
+				Where(In("u.id", Select("user_id").From("admins"))),
+			kind: KindSelect,
+			want: []string{"users", "orders", "payments", "events"},
+		},
+		{
+			name:  "Compound select",
+			query: Union(Select().From("a"), Select().From(Ident("public.b")), Select().From("a")),
+			kind:  KindSelect,
+			want:  []string{"a", "public.b"},
+		},
+		{
+			name:  "Select expression",
+			query: Select("1").From(S("generate_series(1, 10)")),
+			kind:  KindSelect,
+			want:  nil,
+		},
+		{
+			name:  "Insert from select",
+			query: InsertInto("archive").Columns("id").FromSelect(Select("id").From("posts")),
+			kind:  KindInsert,
+			want:  []string{"archive", "posts"},
+		},
+		{
+			name:  "Update from",
+			query: Update("accounts").Set("a", 1).InnerJoin("owners", S("1=1")).From(Table("closures").As("c")),
+			kind:  KindUpdate,
+			want:  []string{"accounts", "owners", "closures"},
+		},
+		{
+			name:  "Delete using",
+			query: DeleteFrom("orders").LeftJoin("items", S("1=1")).Using("customers"),
+			kind:  KindDelete,
+			want:  []string{"orders", "items", "customers"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.Kind(); got != tt.kind {
+				t.Errorf("Query.Kind() got = %v, want %v", got, tt.kind)
+			}
+			if got := tt.query.Tables(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Query.Tables() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
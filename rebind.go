@@ -0,0 +1,146 @@
+package qb
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Placeholder is a Rebinder that replaces every `?` token in a query with
+// Prefix. If Numbered is true, the one based position of the placeholder is
+// appended to the prefix. Custom named styles, such as `:arg1`, can be
+// created by choosing a different prefix.
+//
+// Question marks inside string literals, quoted identifiers, dollar quoted
+// strings, and comments are left untouched. A literal question mark outside
+// of these, such as the PostgreSQL `?|` JSON operator, can be written as `??`.
+// A backslash escapes the next character inside PostgreSQL `E'...'` strings,
+// and inside every string literal if BackslashEscapes is true, as in MySQL.
+type Placeholder struct {
+	Prefix           string
+	Numbered         bool
+	BackslashEscapes bool
+}
+
+var (
+	// Question keeps `?` placeholders and only unescapes `??`.
+	Question = Placeholder{Prefix: "?"}
+	// Dollar uses PostgreSQL style `$1` placeholders.
+	Dollar = Placeholder{Prefix: "$", Numbered: true}
+	// Colon uses Oracle style `:1` placeholders.
+	Colon = Placeholder{Prefix: ":", Numbered: true}
+	// AtP uses SQL Server style `@p1` placeholders.
+	AtP = Placeholder{Prefix: "@p", Numbered: true}
+)
+
+// Rebind replaces the placeholders in the query.
+func (p Placeholder) Rebind(query string) string {
+	var sb strings.Builder
+	sb.Grow(len(query))
+
+	n := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '?':
+			if i+1 < len(query) && query[i+1] == '?' {
+				sb.WriteByte('?')
+				i++
+				continue
+			}
+			n++
+			sb.WriteString(p.Prefix)
+			if p.Numbered {
+				sb.WriteString(strconv.Itoa(n))
+			}
+			continue
+		case c == '\'' || c == '"' || c == '`':
+			escapes := (c != '`' && p.BackslashEscapes) || (c == '\'' && isEscapeString(query, i))
+			end := skipQuoted(query, i, c, escapes)
+			sb.WriteString(query[i:end])
+			i = end - 1
+			continue
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+			sb.WriteString(query[i : i+end])
+			i += end - 1
+			continue
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				end = len(query) - i
+			} else {
+				end += 4
+			}
+			sb.WriteString(query[i : i+end])
+			i += end - 1
+			continue
+		case c == '$':
+			if tag := dollarTag(query[i:]); tag != "" {
+				end := strings.Index(query[i+len(tag):], tag)
+				if end < 0 {
+					end = len(query) - i
+				} else {
+					end += 2 * len(tag)
+				}
+				sb.WriteString(query[i : i+end])
+				i += end - 1
+				continue
+			}
+		}
+		sb.WriteByte(c)
+	}
+
+	return sb.String()
+}
+
+// skipQuoted returns the index just past the quoted section that starts at
+// query[start]. A doubled quote character is treated as an escaped quote, and
+// if backslash is true, so is any character following a backslash.
+func skipQuoted(query string, start int, quote byte, backslash bool) int {
+	for i := start + 1; i < len(query); i++ {
+		if backslash && query[i] == '\\' {
+			i++
+			continue
+		} else if query[i] != quote {
+			continue
+		}
+		if i+1 < len(query) && query[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(query)
+}
+
+// isEscapeString reports whether the quote at query[i] opens a PostgreSQL
+// escape string, such as `E'a\'b'`.
+func isEscapeString(query string, i int) bool {
+	if i == 0 || query[i-1] != 'E' && query[i-1] != 'e' {
+		return false
+	} else if i == 1 {
+		return true
+	}
+	c := query[i-2]
+	return !(c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9')
+}
+
+// dollarTag returns the opening tag of a PostgreSQL dollar quoted string,
+// such as `$$` or `$body$`, if s begins with one.
+func dollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '$':
+			return s[:i+1]
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9' && i > 1:
+		default:
+			return ""
+		}
+	}
+	return ""
+}
@@ -0,0 +1,98 @@
+package qb
+
+import "testing"
+
+func TestPlaceholder_Rebind(t *testing.T) {
+	tests := []struct {
+		name     string
+		rebinder Placeholder
+		query    string
+		want     string
+	}{
+		{
+			name:     "Question",
+			rebinder: Question,
+			query:    "SELECT * FROM t WHERE a=? AND b=?",
+			want:     "SELECT * FROM t WHERE a=? AND b=?",
+		},
+		{
+			name:     "Dollar",
+			rebinder: Dollar,
+			query:    "SELECT * FROM t WHERE a=? AND b=?",
+			want:     "SELECT * FROM t WHERE a=$1 AND b=$2",
+		},
+		{
+			name:     "Colon",
+			rebinder: Colon,
+			query:    "SELECT * FROM t WHERE a=? AND b=?",
+			want:     "SELECT * FROM t WHERE a=:1 AND b=:2",
+		},
+		{
+			name:     "AtP",
+			rebinder: AtP,
+			query:    "SELECT * FROM t WHERE a=? AND b=?",
+			want:     "SELECT * FROM t WHERE a=@p1 AND b=@p2",
+		},
+		{
+			name:     "Postgres escape string",
+			rebinder: Dollar,
+			query:    `SELECT E'x\'?', e'\\', ? FROM t WHERE a = E'it\'s ?' AND b=?`,
+			want:     `SELECT E'x\'?', e'\\', $1 FROM t WHERE a = E'it\'s ?' AND b=$2`,
+		},
+		{
+			name:     "Standard string ending in backslash",
+			rebinder: Dollar,
+			query:    `SELECT 'a\', ?, type'?' FROM t WHERE b=?`,
+			want:     `SELECT 'a\', $1, type'?' FROM t WHERE b=$2`,
+		},
+		{
+			name:     "Backslash escapes",
+			rebinder: Placeholder{Prefix: "$", Numbered: true, BackslashEscapes: true},
+			query:    `SELECT 'it\'s ?' , ? FROM t WHERE a = "x\"?" AND b=? AND ` + "`c\\`=?",
+			want:     `SELECT 'it\'s ?' , $1 FROM t WHERE a = "x\"?" AND b=$2 AND ` + "`c\\`=$3",
+		},
+		{
+			name:     "Named",
+			rebinder: Placeholder{Prefix: ":arg", Numbered: true},
+			query:    "SELECT * FROM t WHERE a=?",
+			want:     "SELECT * FROM t WHERE a=:arg1",
+		},
+		{
+			name:     "String literals",
+			rebinder: Dollar,
+			query:    `SELECT '?', 'it''s ?', "col?" FROM t WHERE a=?`,
+			want:     `SELECT '?', 'it''s ?', "col?" FROM t WHERE a=$1`,
+		},
+		{
+			name:     "Comments",
+			rebinder: Dollar,
+			query:    "SELECT a -- what?\nFROM t /* why? */ WHERE a=?",
+			want:     "SELECT a -- what?\nFROM t /* why? */ WHERE a=$1",
+		},
+		{
+			name:     "Dollar quoted string",
+			rebinder: Dollar,
+			query:    "SELECT $tag$ ? $tag$, $$?$$ FROM t WHERE a=?",
+			want:     "SELECT $tag$ ? $tag$, $$?$$ FROM t WHERE a=$1",
+		},
+		{
+			name:     "Escaped question mark",
+			rebinder: Dollar,
+			query:    "SELECT * FROM t WHERE tags ??| ? AND data ?? 'k'",
+			want:     "SELECT * FROM t WHERE tags ?| $1 AND data ? 'k'",
+		},
+		{
+			name:     "Unterminated literal",
+			rebinder: Dollar,
+			query:    "SELECT 'abc?",
+			want:     "SELECT 'abc?",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rebinder.Rebind(tt.query); got != tt.want {
+				t.Errorf("Placeholder.Rebind() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
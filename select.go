@@ -2,6 +2,7 @@ package qb
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -12,35 +13,58 @@ const (
 	Desc OrderDir = "DESC"
 )
 
+type orderBy struct {
+	col Builder
+	dir OrderDir
+}
+
+// SelectQuery builds a `SELECT` query. It is created with Select or one of
+// the compound functions such as Union.
 type SelectQuery struct {
-	table        string
-	fromSub      *SelectQuery
-	fromSubAlias string
-	cols         []string
-	distinct     []string
-	joins        joins
-	wherePreds   predicates
-	havingPreds  predicates
-	limit        *int
-	offset       *int
-	groupBys     []string
-	orderBys     []string
-	rebinder     Rebinder
-}
-
-func Select(cols ...string) *SelectQuery {
+	with        *WithClause
+	compound    *compound
+	table       Builder
+	cols        []Builder
+	distinct    []string
+	joins       joins
+	wherePreds  predicates
+	havingPreds predicates
+	windows     []namedWindow
+	limit       *int
+	offset      *int
+	bindPaging  bool
+	withTies    bool
+	groupBys    []Builder
+	orderBys    []orderBy
+	seek        *keyset
+	locks       []lock
+	rebinder    Rebinder
+	dialect     Dialect
+	err         error
+}
+
+// Select starts a select query. Each column can be a string or a Builder,
+// such as Raw, whose parameters are bound in the order the columns are
+// listed. A []string adds each of its columns, so a list of column names is
+// passed as Select(cols) rather than Select(cols...). If no columns are
+// provided, `*` is selected.
+func Select(cols ...interface{}) *SelectQuery {
 	if len(cols) == 0 {
-		cols = []string{"*"}
+		cols = []interface{}{"*"}
 	}
-	return &SelectQuery{
-		cols:     cols,
-		groupBys: make([]string, 0),
-		orderBys: make([]string, 0),
+	q := &SelectQuery{
+		groupBys: make([]Builder, 0),
+		orderBys: make([]orderBy, 0),
 	}
+	return q.Select(cols...)
 }
 
-func (q *SelectQuery) Select(cols ...string) *SelectQuery {
-	q.cols = append(q.cols, cols...)
+// Select appends columns to the select list. Each column can be a string or a
+// Builder.
+func (q *SelectQuery) Select(cols ...interface{}) *SelectQuery {
+	exprs, err := toExprs(cols)
+	q.setErr(err)
+	q.cols = append(q.cols, exprs...)
 	return q
 }
 
@@ -52,49 +76,95 @@ func (q *SelectQuery) Distinct(cols ...string) *SelectQuery {
 	return q
 }
 
-func (q *SelectQuery) SetCols(cols ...string) *SelectQuery {
-	q.cols = cols
-	return q
+// SetCols replaces the select list. Each column can be a string or a Builder.
+func (q *SelectQuery) SetCols(cols ...interface{}) *SelectQuery {
+	q.cols = nil
+	return q.Select(cols...)
 }
 
-func (q *SelectQuery) From(table string) *SelectQuery {
-	q.table = table
+// From sets the table to select from. The table can be a string or a Builder
+// such as Ident or an aliased Table. A *SelectQuery is used as a derived
+// table, and can be given an alias with its As method. Without one, it is
+// named `from_sub`. Any other type results in ErrInvalidTable.
+func (q *SelectQuery) From(table interface{}) *SelectQuery {
+	t, err := tableBuilder(table, "from_sub")
+	q.table = t
+	q.setErr(err)
 	return q
 }
 
-func (q *SelectQuery) FromSub(query *SelectQuery, alias string) *SelectQuery {
-	q.fromSub = query
+// setErr records the first error encountered while building up the query.
+// The error is returned by Build.
+func (q *SelectQuery) setErr(err error) {
+	if q.err == nil {
+		q.err = err
+	}
+}
 
+// FromSub selects from the derived table query with the given alias. If the
+// alias is empty, `from_sub` is used.
+func (q *SelectQuery) FromSub(query *SelectQuery, alias string) *SelectQuery {
 	if alias == "" {
 		alias = "from_sub"
 	}
-	q.fromSubAlias = alias
+	return q.From(query.As(alias))
+}
 
-	return q
+// InnerJoin adds an `INNER JOIN`. Like From, the table can be a string, a
+// Builder such as an aliased Table or Lateral, or a *SelectQuery. The
+// condition is written after `ON`, unless it was created with Using.
+func (q *SelectQuery) InnerJoin(table interface{}, condition Builder) *SelectQuery {
+	return q.join(innerJoin, table, condition)
 }
 
-func (q *SelectQuery) InnerJoin(table string, condition Builder) *SelectQuery {
-	q.joins = append(q.joins, newJoin(innerJoin, table, condition))
-	return q
+func (q *SelectQuery) LeftJoin(table interface{}, condition Builder) *SelectQuery {
+	return q.join(leftOuterJoin, table, condition)
 }
 
-func (q *SelectQuery) LeftJoin(table string, condition Builder) *SelectQuery {
-	q.joins = append(q.joins, newJoin(leftOuterJoin, table, condition))
-	return q
+func (q *SelectQuery) RightJoin(table interface{}, condition Builder) *SelectQuery {
+	return q.join(rightOuterJoin, table, condition)
 }
 
-func (q *SelectQuery) RightJoin(table string, condition Builder) *SelectQuery {
-	q.joins = append(q.joins, newJoin(rightOuterJoin, table, condition))
-	return q
+func (q *SelectQuery) FullJoin(table interface{}, condition Builder) *SelectQuery {
+	return q.join(fullOuterJoin, table, condition)
+}
+
+// CrossJoin adds a `CROSS JOIN`, which has no condition.
+func (q *SelectQuery) CrossJoin(table interface{}) *SelectQuery {
+	return q.join(crossJoin, table, nil)
+}
+
+// NaturalJoin adds a `NATURAL INNER JOIN`, which matches rows on all columns
+// with the same names in both tables.
+func (q *SelectQuery) NaturalJoin(table interface{}) *SelectQuery {
+	return q.naturalJoin(innerJoin, table)
+}
+
+// NaturalLeftJoin adds a `NATURAL LEFT OUTER JOIN`.
+func (q *SelectQuery) NaturalLeftJoin(table interface{}) *SelectQuery {
+	return q.naturalJoin(leftOuterJoin, table)
 }
 
-func (q *SelectQuery) FullJoin(table string, condition Builder) *SelectQuery {
-	q.joins = append(q.joins, newJoin(fullOuterJoin, table, condition))
+// NaturalRightJoin adds a `NATURAL RIGHT OUTER JOIN`.
+func (q *SelectQuery) NaturalRightJoin(table interface{}) *SelectQuery {
+	return q.naturalJoin(rightOuterJoin, table)
+}
+
+// NaturalFullJoin adds a `NATURAL FULL OUTER JOIN`.
+func (q *SelectQuery) NaturalFullJoin(table interface{}) *SelectQuery {
+	return q.naturalJoin(fullOuterJoin, table)
+}
+
+func (q *SelectQuery) join(joinType joinType, table interface{}, condition Builder) *SelectQuery {
+	t, err := tableBuilder(table, subAlias("join", len(q.joins)+1))
+	q.setErr(err)
+	q.joins = append(q.joins, newJoin(joinType, t, condition))
 	return q
 }
 
-func (q *SelectQuery) CrossJoin(table string, condition Builder) *SelectQuery {
-	q.joins = append(q.joins, newJoin(crossJoin, table, condition))
+func (q *SelectQuery) naturalJoin(joinType joinType, table interface{}) *SelectQuery {
+	q.join(joinType, table, nil)
+	q.joins[len(q.joins)-1].natural = true
 	return q
 }
 
@@ -123,8 +193,27 @@ func (q *SelectQuery) ClearOffset() *SelectQuery {
 	return q
 }
 
-func (q *SelectQuery) GroupBy(cols ...string) *SelectQuery {
-	q.groupBys = append(q.groupBys, cols...)
+// BindPaging binds the limit and offset as parameters instead of writing them
+// into the query, so pages of different sizes share a prepared statement.
+func (q *SelectQuery) BindPaging() *SelectQuery {
+	q.bindPaging = true
+	return q
+}
+
+// WithTies includes the rows that tie with the last row of the limit in the
+// ORDER BY columns, using `FETCH FIRST n ROWS WITH TIES` or `TOP (n) WITH
+// TIES`. The query must have an ORDER BY clause and a limit.
+func (q *SelectQuery) WithTies() *SelectQuery {
+	q.withTies = true
+	return q
+}
+
+// GroupBy appends expressions to the `GROUP BY` clause. Each expression can be
+// a string or a Builder.
+func (q *SelectQuery) GroupBy(cols ...interface{}) *SelectQuery {
+	exprs, err := toExprs(cols)
+	q.setErr(err)
+	q.groupBys = append(q.groupBys, exprs...)
 	return q
 }
 
@@ -133,8 +222,21 @@ func (q *SelectQuery) Having(pred Builder) *SelectQuery {
 	return q
 }
 
-func (q *SelectQuery) OrderBy(col string, dir OrderDir) *SelectQuery {
-	q.orderBys = append(q.orderBys, fmt.Sprintf("%s %s", col, dir))
+// Window adds a named window to the `WINDOW` clause. Window functions can
+// refer to it with OverWindow.
+func (q *SelectQuery) Window(name string, spec *WindowSpec) *SelectQuery {
+	q.windows = append(q.windows, namedWindow{name, spec})
+	return q
+}
+
+// OrderBy appends an expression to the `ORDER BY` clause. The expression can
+// be a string or a Builder, such as Raw, whose parameters are bound in place.
+func (q *SelectQuery) OrderBy(col interface{}, dir OrderDir) *SelectQuery {
+	exprs, err := toExprs([]interface{}{col})
+	q.setErr(err)
+	if err == nil {
+		q.orderBys = append(q.orderBys, orderBy{exprs[0], dir})
+	}
 	return q
 }
 
@@ -143,85 +245,267 @@ func (q *SelectQuery) RebindWith(r Rebinder) *SelectQuery {
 	return q
 }
 
+// WithDialect sets the dialect used to build the query. If no dialect is set,
+// the package default is used.
+func (q *SelectQuery) WithDialect(d Dialect) *SelectQuery {
+	q.dialect = d
+	return q
+}
+
 func (q *SelectQuery) String() string {
 	s, _, _ := q.Build()
 	return s
 }
 
 func (q *SelectQuery) Build() (string, []interface{}, error) {
-	if (q.table == "" && q.fromSub == nil) || (q.table != "" && q.fromSub != nil) {
+	d := pickDialect(q.dialect)
+	query, params, err := q.buildDialect(d)
+	if err != nil {
+		return "", nil, err
+	}
+	return rebind(query, d, q.rebinder), params, nil
+}
+
+func (q *SelectQuery) buildDialect(d Dialect) (string, []interface{}, error) {
+	if q.err != nil {
+		return "", nil, q.err
+	} else if q.compound != nil {
+		return q.buildCompound(d)
+	} else if q.table == nil {
 		return "", nil, ErrMissingTable
 	}
 
+	if err := checkIdents(q.distinct...); err != nil {
+		return "", nil, err
+	}
+
 	var sb strings.Builder
 	var params []interface{}
-	var where string
-	var err error
+
+	if q.with != nil {
+		w, p, err := q.with.buildDialect(d)
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, p...)
+		sb.WriteString(w)
+		sb.WriteString(" ")
+	}
 
 	sb.WriteString("SELECT ")
 	if len(q.distinct) > 0 {
+		if !d.Supports(FeatureDistinctOn) {
+			return "", nil, ErrUnsupported
+		}
 		fmt.Fprintf(&sb, "DISTINCT ON (%s) ", strings.Join(q.distinct, ", "))
 	} else if q.distinct != nil {
 		sb.WriteString("DISTINCT ")
 	}
-	sb.WriteString(strings.Join(q.cols, ", "))
 
-	if q.table != "" {
-		fmt.Fprintf(&sb, " FROM %s", q.table)
-	} else {
-		s, p, err := q.fromSub.Build()
-		if err != nil {
-			return "", nil, err
+	if q.useTop(d) {
+		if q.withTies && len(q.orderBys) == 0 {
+			return "", nil, ErrMissingOrder
 		}
+		top, p := q.pagingValue(*q.limit)
 		params = append(params, p...)
-		fmt.Fprintf(&sb, " FROM (%s) AS t", s)
+		fmt.Fprintf(&sb, "TOP (%s) ", top)
+		if q.withTies {
+			sb.WriteString("WITH TIES ")
+		}
 	}
 
-	if len(q.joins) > 0 {
-		j, _, _ := q.joins.Build()
-		fmt.Fprintf(&sb, " %s", j)
+	cols, p, err := buildExprs(q.cols, d)
+	if err != nil {
+		return "", nil, err
 	}
+	params = append(params, p...)
+	sb.WriteString(cols)
 
-	if len(q.wherePreds) > 0 {
-		where, params, err = q.wherePreds.Build()
+	t, p, err := buildWith(q.table, d)
+	if err != nil {
+		return "", nil, err
+	}
+	params = append(params, p...)
+	fmt.Fprintf(&sb, " FROM %s", t)
+
+	if len(q.joins) > 0 {
+		j, p, err := q.joins.buildDialect(d)
 		if err != nil {
 			return "", nil, err
 		}
+		params = append(params, p...)
+		fmt.Fprintf(&sb, " %s", j)
+	}
+
+	wherePreds := q.wherePreds
+	if q.seek != nil {
+		wherePreds = append(wherePreds[:len(wherePreds):len(wherePreds)], seekPred{q.orders(), q.seek.vals})
+	}
+	where, p, err := wherePreds.buildDialect(d)
+	if err != nil {
+		return "", nil, err
+	} else if where != "" {
+		params = append(params, p...)
 		sb.WriteString(" WHERE ")
 		sb.WriteString(where)
 	}
 
 	if len(q.groupBys) > 0 {
-		fmt.Fprintf(&sb, " GROUP BY %s", strings.Join(q.groupBys, ", "))
-	}
-
-	if len(q.havingPreds) > 0 {
-		having, p, err := q.havingPreds.Build()
+		groupBys, p, err := buildExprs(q.groupBys, d)
 		if err != nil {
 			return "", nil, err
 		}
+		params = append(params, p...)
+		fmt.Fprintf(&sb, " GROUP BY %s", groupBys)
+	}
 
+	having, p, err := q.havingPreds.buildDialect(d)
+	if err != nil {
+		return "", nil, err
+	} else if having != "" {
 		params = append(params, p...)
 		sb.WriteString(" HAVING ")
 		sb.WriteString(having)
 	}
 
+	if len(q.windows) > 0 {
+		windows := make([]string, len(q.windows))
+		for i, w := range q.windows {
+			if w.spec == nil {
+				return "", nil, ErrInvalidType
+			} else if err := checkIdents(w.name); err != nil {
+				return "", nil, err
+			}
+			spec, p, err := w.spec.buildDialect(d)
+			if err != nil {
+				return "", nil, err
+			}
+			params = append(params, p...)
+			windows[i] = fmt.Sprintf("%s AS (%s)", w.name, spec)
+		}
+		fmt.Fprintf(&sb, " WINDOW %s", strings.Join(windows, ", "))
+	}
+
+	tail, p, err := q.buildTail(d)
+	if err != nil {
+		return "", nil, err
+	}
+	params = append(params, p...)
+	sb.WriteString(tail)
+
+	locks, err := q.buildLocks(d)
+	if err != nil {
+		return "", nil, err
+	}
+	sb.WriteString(locks)
+
+	return sb.String(), params, nil
+}
+
+// buildTail renders the clauses that follow the body of the query: ORDER BY
+// and paging. These are shared by simple and compound queries.
+func (q *SelectQuery) buildTail(d Dialect) (string, []interface{}, error) {
+	var sb strings.Builder
+	var params []interface{}
+
 	if len(q.orderBys) > 0 {
-		fmt.Fprintf(&sb, " ORDER BY %s", strings.Join(q.orderBys, ", "))
+		orders, p, err := buildOrderBys(q.orders(), d)
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, p...)
+		fmt.Fprintf(&sb, " ORDER BY %s", orders)
 	}
 
-	if q.limit != nil {
-		fmt.Fprintf(&sb, " LIMIT %d", *q.limit)
+	paging, p, err := q.buildPaging(d)
+	if err != nil {
+		return "", nil, err
 	}
+	params = append(params, p...)
+	sb.WriteString(paging)
 
-	if q.offset != nil {
-		fmt.Fprintf(&sb, " OFFSET %d", *q.offset)
+	return sb.String(), params, nil
+}
+
+// buildPaging renders the limit and offset using the syntax preferred by the
+// dialect. Limits written as `TOP` by the select list are skipped.
+func (q *SelectQuery) buildPaging(d Dialect) (string, []interface{}, error) {
+	if (q.limit == nil && q.offset == nil) || q.useTop(d) {
+		return "", nil, nil
 	}
 
-	query := sb.String()
-	if q.rebinder != nil {
-		query = q.rebinder.Rebind(query)
+	var sb strings.Builder
+	var params []interface{}
+	switch {
+	case q.withTies && q.limit != nil:
+		if len(q.orderBys) == 0 {
+			return "", nil, ErrMissingOrder
+		} else if !d.Supports(FeatureFetchWithTies) {
+			return "", nil, ErrUnsupported
+		}
+		if q.offset != nil {
+			offset, p := q.pagingValue(*q.offset)
+			params = append(params, p...)
+			fmt.Fprintf(&sb, " OFFSET %s ROWS", offset)
+		}
+		limit, p := q.pagingValue(*q.limit)
+		params = append(params, p...)
+		fmt.Fprintf(&sb, " FETCH FIRST %s ROWS WITH TIES", limit)
+	case d.Supports(FeatureLimitOffset):
+		if q.limit != nil {
+			limit, p := q.pagingValue(*q.limit)
+			params = append(params, p...)
+			fmt.Fprintf(&sb, " LIMIT %s", limit)
+		} else if d.Supports(FeatureOffsetRequiresLimit | FeatureNegativeLimit) {
+			sb.WriteString(" LIMIT -1")
+		} else if d.Supports(FeatureOffsetRequiresLimit) {
+			sb.WriteString(" LIMIT 18446744073709551615")
+		}
+		if q.offset != nil {
+			offset, p := q.pagingValue(*q.offset)
+			params = append(params, p...)
+			fmt.Fprintf(&sb, " OFFSET %s", offset)
+		}
+	case d.Supports(FeatureOffsetFetch):
+		// SQL Server rejects OFFSET and FETCH without an ORDER BY clause.
+		if len(q.orderBys) == 0 {
+			return "", nil, ErrMissingOrder
+		}
+		offset := 0
+		if q.offset != nil {
+			offset = *q.offset
+		}
+		o, p := q.pagingValue(offset)
+		params = append(params, p...)
+		fmt.Fprintf(&sb, " OFFSET %s ROWS", o)
+		if q.limit != nil {
+			limit, p := q.pagingValue(*q.limit)
+			params = append(params, p...)
+			fmt.Fprintf(&sb, " FETCH NEXT %s ROWS ONLY", limit)
+		}
+	default:
+		return "", nil, ErrUnsupported
+	}
+	return sb.String(), params, nil
+}
+
+// useTop reports whether the limit is written as `TOP (n)` in the select list.
+// This is the case for dialects that support TOP but not LIMIT, or `FETCH
+// FIRST ... WITH TIES`, when the query has a limit but no offset.
+func (q *SelectQuery) useTop(d Dialect) bool {
+	if q.compound != nil || q.limit == nil || q.offset != nil || !d.Supports(FeatureTop) {
+		return false
+	} else if q.withTies {
+		return !d.Supports(FeatureFetchWithTies)
 	}
+	return !d.Supports(FeatureLimitOffset)
+}
 
-	return query, params, nil
+// pagingValue returns n formatted into the query, or a placeholder and n as a
+// parameter if paging values are bound.
+func (q *SelectQuery) pagingValue(n int) (string, []interface{}) {
+	if q.bindPaging {
+		return "?", []interface{}{n}
+	}
+	return strconv.Itoa(n), nil
 }
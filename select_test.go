@@ -48,6 +48,13 @@ func Test_selectQuery_String(t *testing.T) {
 			want1:   nil,
 			wantErr: false,
 		},
+		{
+			name:    "String slice columns",
+			query:   Select([]string{"a", "b"}).From("test_table").GroupBy([]string{"a", "b"}).SetCols([]string{"a"}, "COUNT(*)"),
+			want:    "SELECT a, COUNT(*) FROM test_table GROUP BY a, b",
+			want1:   nil,
+			wantErr: false,
+		},
 		{
 			name:    "Having clause",
 			query:   Select("id").From("test_table").GroupBy("id").Having(Pred{"COUNT(*)", ">", 2}),
@@ -104,6 +111,60 @@ func Test_selectQuery_String(t *testing.T) {
 			want1:   nil,
 			wantErr: false,
 		},
+		{
+			name: "Parameterized expressions",
+			query: Select("id", Raw("COALESCE(name, ?) AS name", []interface{}{"unknown"})).
+				From("test_table").
+				Where(Eq("a", 1)).
+				GroupBy("id", Raw("date_trunc(?, created_at)", []interface{}{"day"})).
+				Having(Gt("COUNT(*)", 2)).
+				OrderBy(Raw("similarity(name, ?)", []interface{}{"foo"}), Desc).
+				OrderBy("id", Asc),
+			want:    "SELECT id, COALESCE(name, ?) AS name FROM test_table WHERE a=? GROUP BY id, date_trunc(?, created_at) HAVING COUNT(*)>? ORDER BY similarity(name, ?) DESC, id ASC",
+			want1:   []interface{}{"unknown", 1, "day", 2, "foo"},
+			wantErr: false,
+		},
+		{
+			name:    "Invalid column type",
+			query:   Select(1).From("test_table"),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Set cols",
+			query:   Select("a").SetCols("b", S("c")).From("test_table"),
+			want:    "SELECT b, c FROM test_table",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name: "Aliased scalar subquery",
+			query: Select("id", Select("COUNT(*)").From("orders").Where(S("orders.user_id=users.id")).Where(Gt("total", 10)).As("order_count")).
+				From("users").
+				Where(Eq("active", true)),
+			want:    "SELECT id, (SELECT COUNT(*) FROM orders WHERE orders.user_id=users.id AND total>?) AS order_count FROM users WHERE active=?",
+			want1:   []interface{}{10, true},
+			wantErr: false,
+		},
+		{
+			name:    "Unaliased scalar subquery",
+			query:   Select("id", Select("MAX(total)").From("orders")).From("users"),
+			want:    "SELECT id, (SELECT MAX(total) FROM orders) FROM users",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name: "Using, natural, and cross joins",
+			query: Select().From("a").
+				InnerJoin("b", Using("id")).
+				NaturalLeftJoin("c").
+				CrossJoin("d").
+				Where(Eq("a.x", 1)),
+			want:    "SELECT * FROM a INNER JOIN b USING (id) NATURAL LEFT OUTER JOIN c CROSS JOIN d WHERE a.x=?",
+			want1:   []interface{}{1},
+			wantErr: false,
+		},
 		// {
 		// 	name:    "Select from derived table",
 		// 	query:   Select(""),
@@ -116,14 +177,14 @@ func Test_selectQuery_String(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			got, got1, err := tt.query.Build()
 			if (err != nil) != tt.wantErr {
-				t.Errorf("insertQuery.String() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("InsertQuery.String() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if got != tt.want {
-				t.Errorf("insertQuery.String() got = %v, want %v", got, tt.want)
+				t.Errorf("InsertQuery.String() got = %v, want %v", got, tt.want)
 			}
 			if !reflect.DeepEqual(got1, tt.want1) {
-				t.Errorf("insertQuery.String() got1 = %v, want %v", got1, tt.want1)
+				t.Errorf("InsertQuery.String() got1 = %v, want %v", got1, tt.want1)
 			}
 		})
 	}
@@ -201,3 +262,134 @@ func Benchmark_SelectQuery(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 	}
 }
+
+func TestSelectQuery_Paging(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   *SelectQuery
+		want    string
+		want1   []interface{}
+		wantErr bool
+	}{
+		{
+			name:    "Bound limit and offset",
+			query:   Select().From("t").Where(Eq("a", 1)).Limit(10).Offset(20).BindPaging().WithDialect(Postgres),
+			want:    "SELECT * FROM t WHERE a=$1 LIMIT $2 OFFSET $3",
+			want1:   []interface{}{1, 10, 20},
+			wantErr: false,
+		},
+		{
+			name:    "Bound offset fetch",
+			query:   Select().From("t").OrderBy("a", Asc).Limit(10).Offset(20).BindPaging().WithDialect(SQLServer),
+			want:    "SELECT * FROM t ORDER BY a ASC OFFSET @p1 ROWS FETCH NEXT @p2 ROWS ONLY",
+			want1:   []interface{}{20, 10},
+			wantErr: false,
+		},
+		{
+			name:    "SQL Server top",
+			query:   Select("a").Distinct().From("t").Where(Eq("b", 1)).Limit(5).WithDialect(SQLServer),
+			want:    "SELECT DISTINCT TOP (5) a FROM t WHERE b=@p1",
+			want1:   []interface{}{1},
+			wantErr: false,
+		},
+		{
+			name:    "Bound top",
+			query:   Select("a", Raw("? AS b", []interface{}{"x"})).From("t").Where(Eq("c", 1)).Limit(5).BindPaging().WithDialect(SQLServer),
+			want:    "SELECT TOP (@p1) a, @p2 AS b FROM t WHERE c=@p3",
+			want1:   []interface{}{5, "x", 1},
+			wantErr: false,
+		},
+		{
+			name:    "SQL Server top with ties",
+			query:   Select().From("t").OrderBy("score", Desc).Limit(3).WithTies().WithDialect(SQLServer),
+			want:    "SELECT TOP (3) WITH TIES * FROM t ORDER BY score DESC",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Fetch first with ties",
+			query:   Select().From("t").OrderBy("score", Desc).Limit(3).Offset(6).WithTies().WithDialect(Postgres),
+			want:    "SELECT * FROM t ORDER BY score DESC OFFSET 6 ROWS FETCH FIRST 3 ROWS WITH TIES",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "With ties without order",
+			query:   Select().From("t").Limit(3).WithTies(),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "With ties unsupported",
+			query:   Select().From("t").OrderBy("score", Desc).Limit(3).WithTies().WithDialect(MySQL),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "MySQL offset only",
+			query:   Select().From("t").Offset(5).WithDialect(MySQL),
+			want:    "SELECT * FROM t LIMIT 18446744073709551615 OFFSET 5",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "SQLite bound offset only",
+			query:   Select().From("t").Offset(5).BindPaging().WithDialect(SQLite),
+			want:    "SELECT * FROM t LIMIT -1 OFFSET ?",
+			want1:   []interface{}{5},
+			wantErr: false,
+		},
+		{
+			name:    "Postgres offset only",
+			query:   Select().From("t").Offset(5).WithDialect(Postgres),
+			want:    "SELECT * FROM t OFFSET 5",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "SQL Server offset only",
+			query:   Select().From("t").OrderBy("a", Asc).Offset(5).WithDialect(SQLServer),
+			want:    "SELECT * FROM t ORDER BY a ASC OFFSET 5 ROWS",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "SQL Server offset without order",
+			query:   Select().From("t").Offset(5).WithDialect(SQLServer),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "SQL Server compound limit without order",
+			query:   Union(Select("a").From("t1"), Select("a").From("t2")).Limit(3).WithDialect(SQLServer),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Compound limit uses offset fetch",
+			query:   Union(Select("a").From("t1"), Select("a").From("t2")).OrderBy("a", Asc).Limit(5).WithDialect(SQLServer),
+			want:    "(SELECT a FROM t1) UNION (SELECT a FROM t2) ORDER BY a ASC OFFSET 0 ROWS FETCH NEXT 5 ROWS ONLY",
+			want1:   nil,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1, err := tt.query.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SelectQuery.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("SelectQuery.Build() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("SelectQuery.Build() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
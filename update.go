@@ -7,38 +7,155 @@ import (
 
 type Excluded string
 
-type updateQuery struct {
+// Default sets a column to its default value when passed to Set.
+const Default = S("DEFAULT")
+
+// Now returns the current timestamp expression `CURRENT_TIMESTAMP`, which is
+// supported by every dialect.
+func Now() Builder { return S("CURRENT_TIMESTAMP") }
+
+// Incr returns the expression `col+?` with n as its parameter, which
+// increments a column when passed to Set, as in Set("views", Incr("views", 1)).
+func Incr(col string, n interface{}) Builder { return incr{col, n} }
+
+type incr struct {
+	col string
+	n   interface{}
+}
+
+func (i incr) Build() (string, []interface{}, error) {
+	if err := checkIdents(i.col); err != nil {
+		return "", nil, err
+	}
+	return i.col + "+?", []interface{}{i.n}, nil
+}
+
+// UpdateQuery builds an `UPDATE` query. It is created with Update.
+type UpdateQuery struct {
+	with       *WithClause
 	table      string
+	joins      joins
 	setPairs   map[string]interface{}
+	from       []Builder
 	wherePreds predicates
+	orderBys   []orderBy
+	limit      *int
+	returning  []string
 	rebinder   Rebinder
+	dialect    Dialect
+	err        error
 }
 
-func Update(table string) *updateQuery {
-	return &updateQuery{table: table, setPairs: make(map[string]interface{})}
+func Update(table string) *UpdateQuery {
+	return &UpdateQuery{table: table, setPairs: make(map[string]interface{})}
 }
 
-func (q *updateQuery) Set(col string, val interface{}) *updateQuery {
+// Set sets col to val. A Builder value, such as Incr, Default, Now, or Raw, is
+// written in place with its parameters, and a *SelectQuery is written as a
+// subquery. Any other value is bound as a parameter.
+func (q *UpdateQuery) Set(col string, val interface{}) *UpdateQuery {
 	q.setPairs[col] = val
 	return q
 }
 
-func (q *updateQuery) Where(pred Builder) *updateQuery {
+// From adds a table to the `FROM` clause, whose columns can be used in the
+// SET values and the WHERE clause. Like SelectQuery.From, the table can be a
+// string, a Builder, or a *SelectQuery, which is named `from_subN` if it has
+// no alias. `UPDATE ... FROM` is supported by PostgreSQL, SQLite, and SQL
+// Server.
+func (q *UpdateQuery) From(table interface{}) *UpdateQuery {
+	t, err := tableBuilder(table, subAlias("from", len(q.from)+1))
+	q.setErr(err)
+	if t != nil {
+		q.from = append(q.from, t)
+	}
+	return q
+}
+
+// InnerJoin joins a table to the updated table, as in the MySQL multi-table
+// `UPDATE a INNER JOIN b ON ... SET ...`.
+func (q *UpdateQuery) InnerJoin(table interface{}, condition Builder) *UpdateQuery {
+	return q.join(innerJoin, table, condition)
+}
+
+// LeftJoin joins a table to the updated table with a `LEFT OUTER JOIN`.
+func (q *UpdateQuery) LeftJoin(table interface{}, condition Builder) *UpdateQuery {
+	return q.join(leftOuterJoin, table, condition)
+}
+
+func (q *UpdateQuery) join(joinType joinType, table interface{}, condition Builder) *UpdateQuery {
+	t, err := tableBuilder(table, subAlias("join", len(q.joins)+1))
+	q.setErr(err)
+	q.joins = append(q.joins, newJoin(joinType, t, condition))
+	return q
+}
+
+func (q *UpdateQuery) Where(pred Builder) *UpdateQuery {
 	q.wherePreds = append(q.wherePreds, pred)
 	return q
 }
 
-func (q *updateQuery) RebindWith(r Rebinder) *updateQuery {
+// OrderBy appends an expression to the `ORDER BY` clause, which sets the
+// order rows are updated in when combined with Limit. It is supported by
+// MySQL, and by SQLite when built with SQLITE_ENABLE_UPDATE_DELETE_LIMIT.
+func (q *UpdateQuery) OrderBy(col interface{}, dir OrderDir) *UpdateQuery {
+	exprs, err := toExprs([]interface{}{col})
+	q.setErr(err)
+	if err == nil {
+		q.orderBys = append(q.orderBys, orderBy{exprs[0], dir})
+	}
+	return q
+}
+
+// Limit limits the number of updated rows. Like OrderBy, it is supported by
+// MySQL and SQLite.
+func (q *UpdateQuery) Limit(l int) *UpdateQuery {
+	q.limit = &l
+	return q
+}
+
+// Returning adds columns of the updated rows to the `RETURNING` clause.
+func (q *UpdateQuery) Returning(cols ...string) *UpdateQuery {
+	q.returning = append(q.returning, cols...)
+	return q
+}
+
+// setErr records the first error encountered while building up the query.
+func (q *UpdateQuery) setErr(err error) {
+	if q.err == nil {
+		q.err = err
+	}
+}
+
+func (q *UpdateQuery) RebindWith(r Rebinder) *UpdateQuery {
 	q.rebinder = r
 	return q
 }
 
-func (q *updateQuery) Build() (string, []interface{}, error) {
-	return q.build(true)
+// WithDialect sets the dialect used to build the query. If no dialect is set,
+// the package default is used.
+func (q *UpdateQuery) WithDialect(d Dialect) *UpdateQuery {
+	q.dialect = d
+	return q
 }
 
-func (q *updateQuery) build(tableRequired bool) (string, []interface{}, error) {
-	if q.table == "" && tableRequired {
+func (q *UpdateQuery) Build() (string, []interface{}, error) {
+	d := pickDialect(q.dialect)
+	query, params, err := q.build(d, true)
+	if err != nil {
+		return "", nil, err
+	}
+	return rebind(query, d, q.rebinder), params, nil
+}
+
+func (q *UpdateQuery) buildDialect(d Dialect) (string, []interface{}, error) {
+	return q.build(d, true)
+}
+
+func (q *UpdateQuery) build(d Dialect, tableRequired bool) (string, []interface{}, error) {
+	if q.err != nil {
+		return "", nil, q.err
+	} else if q.table == "" && tableRequired {
 		return "", nil, ErrMissingTable
 	} else if len(q.setPairs) == 0 {
 		return "", nil, ErrMissingSetPairs
@@ -47,20 +164,65 @@ func (q *updateQuery) build(tableRequired bool) (string, []interface{}, error) {
 	var sb strings.Builder
 	params := make([]interface{}, 0)
 
+	if q.with != nil {
+		w, p, err := q.with.buildDialect(d)
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, p...)
+		sb.WriteString(w)
+		sb.WriteString(" ")
+	}
+
 	sb.WriteString("UPDATE ")
 
 	if q.table != "" {
-		fmt.Fprintf(&sb, `"%s" `, q.table)
+		table, _, err := Ident(q.table).buildDialect(d)
+		if err != nil {
+			return "", nil, err
+		}
+		fmt.Fprintf(&sb, "%s ", table)
+	}
+
+	if len(q.joins) > 0 {
+		if !d.Supports(FeatureUpdateJoin) {
+			return "", nil, ErrUnsupported
+		}
+		j, p, err := q.joins.buildDialect(d)
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, p...)
+		fmt.Fprintf(&sb, "%s ", j)
 	}
 	sb.WriteString("SET ")
 
 	keys := orderKeys(q.setPairs)
+	if err := checkIdents(keys...); err != nil {
+		return "", nil, err
+	}
 
 	sets := make([]string, 0)
 	for _, k := range keys {
 		switch v := q.setPairs[k].(type) {
 		case Excluded:
+			if err := checkIdents(string(v)); err != nil {
+				return "", nil, err
+			}
 			sets = append(sets, fmt.Sprintf("%s=EXCLUDED.%s", k, v))
+		case Builder:
+			if isNil(v) {
+				return "", nil, ErrInvalidType
+			}
+			expr, p, err := buildWith(v, d)
+			if err != nil {
+				return "", nil, err
+			}
+			if _, ok := v.(*SelectQuery); ok {
+				expr = "(" + expr + ")"
+			}
+			sets = append(sets, fmt.Sprintf("%s=%s", k, expr))
+			params = append(params, p...)
 		default:
 			sets = append(sets, fmt.Sprintf("%s=?", k))
 			params = append(params, q.setPairs[k])
@@ -68,21 +230,68 @@ func (q *updateQuery) build(tableRequired bool) (string, []interface{}, error) {
 	}
 	sb.WriteString(strings.Join(sets, ", "))
 
-	if len(q.wherePreds) > 0 {
-		q, p, err := q.wherePreds.Build()
+	if len(q.from) > 0 {
+		if !d.Supports(FeatureUpdateFrom) {
+			return "", nil, ErrUnsupported
+		}
+		from, p, err := buildExprs(q.from, d)
 		if err != nil {
 			return "", nil, err
 		}
+		params = append(params, p...)
+		fmt.Fprintf(&sb, " FROM %s", from)
+	}
+
+	where, p, err := q.wherePreds.buildDialect(d)
+	if err != nil {
+		return "", nil, err
+	} else if where != "" {
 		sb.WriteString(" WHERE ")
-		sb.WriteString(q)
+		sb.WriteString(where)
 		params = append(params, p...)
 	}
 
-	query := sb.String()
+	tail, p, err := buildModifyTail(q.orderBys, q.limit, len(q.joins) > 0, d)
+	if err != nil {
+		return "", nil, err
+	}
+	params = append(params, p...)
+	sb.WriteString(tail)
+
+	if len(q.returning) > 0 {
+		if !d.Supports(FeatureReturning) {
+			return "", nil, ErrUnsupported
+		} else if err := checkIdents(q.returning...); err != nil {
+			return "", nil, err
+		}
+		sb.WriteString(" RETURNING ")
+		sb.WriteString(strings.Join(q.returning, ", "))
+	}
+
+	return sb.String(), params, nil
+}
 
-	if q.rebinder != nil {
-		query = q.rebinder.Rebind(query)
+// buildModifyTail builds the `ORDER BY` and `LIMIT` clauses of an update or
+// delete query. They cannot be combined with joins.
+func buildModifyTail(orders []orderBy, limit *int, joined bool, d Dialect) (string, []interface{}, error) {
+	if len(orders) == 0 && limit == nil {
+		return "", nil, nil
+	} else if !d.Supports(FeatureModifyLimit) || joined {
+		return "", nil, ErrUnsupported
 	}
 
-	return query, params, nil
+	var sb strings.Builder
+	var params []interface{}
+	if len(orders) > 0 {
+		o, p, err := buildOrderBys(orders, d)
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, p...)
+		fmt.Fprintf(&sb, " ORDER BY %s", o)
+	}
+	if limit != nil {
+		fmt.Fprintf(&sb, " LIMIT %d", *limit)
+	}
+	return sb.String(), params, nil
 }
@@ -8,7 +8,7 @@ import (
 func Test_updateQuery_String(t *testing.T) {
 	tests := []struct {
 		name    string
-		query   *updateQuery
+		query   *UpdateQuery
 		want    string
 		want1   []interface{}
 		wantErr bool
@@ -34,6 +34,107 @@ func Test_updateQuery_String(t *testing.T) {
 			want1:   []interface{}{"b", 1, "d", false},
 			wantErr: false,
 		},
+		{
+			name: "Update from",
+			query: Update("accounts").
+				Set("balance", 0).
+				From(Table("closures").As("c")).
+				Where(S("c.account_id=accounts.id")).
+				Where(Gt("c.closed_at", "2020-01-01")).
+				Returning("accounts.id").
+				WithDialect(Postgres),
+			want:    `UPDATE "accounts" SET balance=$1 FROM closures AS c WHERE c.account_id=accounts.id AND c.closed_at>$2 RETURNING accounts.id`,
+			want1:   []interface{}{0, "2020-01-01"},
+			wantErr: false,
+		},
+		{
+			name: "MySQL update join",
+			query: Update("orders").
+				InnerJoin(Table("customers").As("c"), And{S("c.id=orders.customer_id"), Eq("c.region", "eu")}).
+				Set("vat", true).
+				WithDialect(MySQL),
+			want:    "UPDATE `orders` INNER JOIN customers AS c ON (c.id=orders.customer_id AND c.region=?) SET vat=?",
+			want1:   []interface{}{"eu", true},
+			wantErr: false,
+		},
+		{
+			name:    "MySQL order by and limit",
+			query:   Update("jobs").Set("status", "claimed").Where(Eq("status", "queued")).OrderBy("id", Asc).Limit(10).WithDialect(MySQL),
+			want:    "UPDATE `jobs` SET status=? WHERE status=? ORDER BY id ASC LIMIT 10",
+			want1:   []interface{}{"claimed", "queued"},
+			wantErr: false,
+		},
+		{
+			name:    "Postgres limit unsupported",
+			query:   Update("jobs").Set("status", "claimed").Limit(10).WithDialect(Postgres),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Postgres join unsupported",
+			query:   Update("a").InnerJoin("b", S("a.id=b.id")).Set("x", 1).WithDialect(Postgres),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "MySQL from unsupported",
+			query:   Update("a").Set("x", 1).From("b").WithDialect(MySQL),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "MySQL returning unsupported",
+			query:   Update("a").Set("x", 1).Returning("id").WithDialect(MySQL),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Join with limit unsupported",
+			query:   Update("a").InnerJoin("b", S("a.id=b.id")).Set("x", 1).Limit(1).WithDialect(MySQL),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "From subquery default alias",
+			query:   Update("a").Set("x", S("from_sub2.x")).From("b").From(Select("x").From("c")).Where(Eq("a.id", 1)).WithDialect(Postgres),
+			want:    `UPDATE "a" SET x=from_sub2.x FROM b, (SELECT x FROM c) AS from_sub2 WHERE a.id=$1`,
+			want1:   []interface{}{1},
+			wantErr: false,
+		},
+		{
+			name:    "Invalid from table",
+			query:   Update("a").Set("x", 1).From(1),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name: "Update with expression values",
+			query: Update("posts").
+				Set("views", Incr("views", 1)).
+				Set("updated_at", Now()).
+				Set("title", Default).
+				Set("score", Raw("score * ?", []interface{}{2})).
+				Set("author", Select("name").From("users").Where(S("users.id=posts.user_id")).Where(Eq("active", true))).
+				Set("body", "text").
+				Where(Eq("id", 9)).
+				WithDialect(Postgres),
+			want:    `UPDATE "posts" SET author=(SELECT name FROM users WHERE users.id=posts.user_id AND active=$1), body=$2, score=score * $3, title=DEFAULT, updated_at=CURRENT_TIMESTAMP, views=views+$4 WHERE id=$5`,
+			want1:   []interface{}{true, "text", 2, 1, 9},
+			wantErr: false,
+		},
+		{
+			name:    "Nil subquery value",
+			query:   Update("t").Set("a", (*SelectQuery)(nil)),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
 		{
 			name:    "Missing table",
 			query:   Update("").Set("a", "b"),
@@ -53,14 +154,14 @@ func Test_updateQuery_String(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			got, got1, err := tt.query.Build()
 			if (err != nil) != tt.wantErr {
-				t.Errorf("insertQuery.String() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("InsertQuery.String() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if got != tt.want {
-				t.Errorf("insertQuery.String() got = %v, want %v", got, tt.want)
+				t.Errorf("InsertQuery.String() got = %v, want %v", got, tt.want)
 			}
 			if !reflect.DeepEqual(got1, tt.want1) {
-				t.Errorf("insertQuery.String() got1 = %v, want %v", got1, tt.want1)
+				t.Errorf("InsertQuery.String() got1 = %v, want %v", got1, tt.want1)
 			}
 		})
 	}
@@ -0,0 +1,176 @@
+package qb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FrameBound is the start or end of a window frame. Use the constants
+// UnboundedPreceding, CurrentRow, and UnboundedFollowing or the functions
+// Preceding and Following.
+type FrameBound string
+
+const (
+	UnboundedPreceding FrameBound = "UNBOUNDED PRECEDING"
+	CurrentRow         FrameBound = "CURRENT ROW"
+	UnboundedFollowing FrameBound = "UNBOUNDED FOLLOWING"
+)
+
+// Preceding returns the frame bound `n PRECEDING`.
+func Preceding(n int) FrameBound { return FrameBound(strconv.Itoa(n) + " PRECEDING") }
+
+// Following returns the frame bound `n FOLLOWING`.
+func Following(n int) FrameBound { return FrameBound(strconv.Itoa(n) + " FOLLOWING") }
+
+// WindowSpec represents a window specification as used by an `OVER` clause
+// or a named window in a `WINDOW` clause.
+type WindowSpec struct {
+	base        string
+	partitionBy []string
+	orderBys    []orderBy
+	frame       string
+}
+
+// Window returns an empty window specification.
+func Window() *WindowSpec {
+	return &WindowSpec{}
+}
+
+// Base makes the specification extend the named window.
+func (w *WindowSpec) Base(name string) *WindowSpec {
+	w.base = name
+	return w
+}
+
+// PartitionBy appends columns to the `PARTITION BY` clause.
+func (w *WindowSpec) PartitionBy(cols ...string) *WindowSpec {
+	w.partitionBy = append(w.partitionBy, cols...)
+	return w
+}
+
+// OrderBy appends a column to the `ORDER BY` clause.
+func (w *WindowSpec) OrderBy(col string, dir OrderDir) *WindowSpec {
+	w.orderBys = append(w.orderBys, orderBy{identifier(col), dir})
+	return w
+}
+
+// Rows sets a `ROWS` frame. If end is empty, only the start is written.
+func (w *WindowSpec) Rows(start, end FrameBound) *WindowSpec { return w.setFrame("ROWS", start, end) }
+
+// Range sets a `RANGE` frame. If end is empty, only the start is written.
+func (w *WindowSpec) Range(start, end FrameBound) *WindowSpec { return w.setFrame("RANGE", start, end) }
+
+// Groups sets a `GROUPS` frame. If end is empty, only the start is written.
+// GROUPS frames are not supported by MySQL or SQL Server.
+func (w *WindowSpec) Groups(start, end FrameBound) *WindowSpec {
+	return w.setFrame("GROUPS", start, end)
+}
+
+func (w *WindowSpec) setFrame(unit string, start, end FrameBound) *WindowSpec {
+	if end == "" {
+		w.frame = fmt.Sprintf("%s %s", unit, start)
+	} else {
+		w.frame = fmt.Sprintf("%s BETWEEN %s AND %s", unit, start, end)
+	}
+	return w
+}
+
+// String returns the specification without surrounding parentheses. If the
+// specification is invalid, an empty string is returned.
+func (w *WindowSpec) String() string {
+	s, _, _ := w.Build()
+	return s
+}
+
+// Build returns the specification without surrounding parentheses. In strict
+// mode, ErrInvalidIdent is returned if it contains an invalid identifier.
+func (w *WindowSpec) Build() (string, []interface{}, error) { return w.buildDialect(defaultDialect()) }
+
+func (w *WindowSpec) buildDialect(d Dialect) (string, []interface{}, error) {
+	var parts []string
+	var params []interface{}
+
+	if w.base != "" {
+		if err := checkIdents(w.base); err != nil {
+			return "", nil, err
+		}
+		parts = append(parts, w.base)
+	}
+
+	if len(w.partitionBy) > 0 {
+		if err := checkIdents(w.partitionBy...); err != nil {
+			return "", nil, err
+		}
+		parts = append(parts, "PARTITION BY "+strings.Join(w.partitionBy, ", "))
+	}
+
+	if len(w.orderBys) > 0 {
+		orders, p, err := buildOrderBys(w.orderBys, d)
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, p...)
+		parts = append(parts, "ORDER BY "+orders)
+	}
+
+	if w.frame != "" {
+		parts = append(parts, w.frame)
+	}
+
+	return strings.Join(parts, " "), params, nil
+}
+
+// WindowFunc is a window function call, such as
+// `ROW_NUMBER() OVER (PARTITION BY dept ORDER BY salary DESC)`. It is created
+// with Over or OverWindow and can be used in the select list or in ORDER BY,
+// including in strict mode.
+type WindowFunc struct {
+	fn     string
+	spec   *WindowSpec
+	window string
+}
+
+// Over returns the window function call fn applied over the window spec. The
+// function call is written as is. A nil spec results in `fn OVER ()`.
+func Over(fn string, spec *WindowSpec) WindowFunc {
+	return WindowFunc{fn: fn, spec: spec}
+}
+
+// OverWindow returns the window function call fn applied over the named
+// window, such as `SUM(amount) OVER w`.
+func OverWindow(fn, name string) WindowFunc {
+	return WindowFunc{fn: fn, window: name}
+}
+
+// As returns the window function call with an alias, such as
+// `ROW_NUMBER() OVER (...) AS rn`.
+func (f WindowFunc) As(alias string) Builder { return aliased{f, alias} }
+
+// Build builds the window function call. Errors of the window spec are
+// returned.
+func (f WindowFunc) Build() (string, []interface{}, error) { return f.buildDialect(defaultDialect()) }
+
+func (f WindowFunc) buildDialect(d Dialect) (string, []interface{}, error) {
+	if f.fn == "" {
+		return "", nil, ErrInvalidType
+	} else if f.window != "" {
+		if err := checkIdents(f.window); err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("%s OVER %s", f.fn, f.window), nil, nil
+	} else if f.spec == nil {
+		return f.fn + " OVER ()", nil, nil
+	}
+
+	spec, p, err := f.spec.buildDialect(d)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s OVER (%s)", f.fn, spec), p, nil
+}
+
+type namedWindow struct {
+	name string
+	spec *WindowSpec
+}
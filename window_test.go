@@ -0,0 +1,119 @@
+package qb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWindowSpec_String(t *testing.T) {
+	tests := []struct {
+		name string
+		spec *WindowSpec
+		want string
+	}{
+		{"Empty", Window(), ""},
+		{"Partition", Window().PartitionBy("a", "b"), "PARTITION BY a, b"},
+		{"Order", Window().OrderBy("a", Asc).OrderBy("b", Desc), "ORDER BY a ASC, b DESC"},
+		{
+			name: "Rows frame",
+			spec: Window().PartitionBy("a").OrderBy("b", Asc).Rows(UnboundedPreceding, CurrentRow),
+			want: "PARTITION BY a ORDER BY b ASC ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW",
+		},
+		{"Range frame", Window().OrderBy("b", Asc).Range(Preceding(3), Following(3)), "ORDER BY b ASC RANGE BETWEEN 3 PRECEDING AND 3 FOLLOWING"},
+		{"Groups start only", Window().OrderBy("b", Asc).Groups(UnboundedPreceding, ""), "ORDER BY b ASC GROUPS UNBOUNDED PRECEDING"},
+		{"Base window", Window().Base("w").Rows(CurrentRow, UnboundedFollowing), "w ROWS BETWEEN CURRENT ROW AND UNBOUNDED FOLLOWING"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.spec.String(); got != tt.want {
+				t.Errorf("WindowSpec.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindow_Select(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   *SelectQuery
+		want    string
+		want1   []interface{}
+		wantErr bool
+	}{
+		{
+			name: "Window function in select list and order by",
+			query: Select("id", Over("ROW_NUMBER()", Window().PartitionBy("dept").OrderBy("salary", Desc)).As("rn")).
+				From("employees").
+				OrderBy(Over("RANK()", Window().OrderBy("salary", Desc)), Asc),
+			want:    "SELECT id, ROW_NUMBER() OVER (PARTITION BY dept ORDER BY salary DESC) AS rn FROM employees ORDER BY RANK() OVER (ORDER BY salary DESC) ASC",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name: "Named window",
+			query: Select("id", OverWindow("SUM(amount)", "w"), Over("AVG(amount)", Window().Base("w").Rows(Preceding(1), CurrentRow))).
+				From("payments").
+				Where(Gt("amount", 0)).
+				Window("w", Window().PartitionBy("account_id").OrderBy("paid_at", Asc)),
+			want:    "SELECT id, SUM(amount) OVER w, AVG(amount) OVER (w ROWS BETWEEN 1 PRECEDING AND CURRENT ROW) FROM payments WHERE amount>? WINDOW w AS (PARTITION BY account_id ORDER BY paid_at ASC)",
+			want1:   []interface{}{0},
+			wantErr: false,
+		},
+		{
+			name:    "Empty spec",
+			query:   Select("id", Over("COUNT(*)", nil).As("total")).From("payments"),
+			want:    "SELECT id, COUNT(*) OVER () AS total FROM payments",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Missing spec",
+			query:   Select().From("payments").Window("w", nil),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1, err := tt.query.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SelectQuery.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("SelectQuery.Build() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("SelectQuery.Build() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func TestWindow_Strict(t *testing.T) {
+	SetStrictIdents(true)
+	defer SetStrictIdents(false)
+
+	q := Select("id", Over("ROW_NUMBER()", Window().PartitionBy("dept").OrderBy("salary", Desc)).As("rn"), OverWindow("SUM(amount)", "w")).
+		From("employees").
+		OrderBy(Over("RANK()", Window().OrderBy("salary", Desc)), Asc).
+		Window("w", Window().PartitionBy("dept"))
+	want := "SELECT id, ROW_NUMBER() OVER (PARTITION BY dept ORDER BY salary DESC) AS rn, SUM(amount) OVER w FROM employees WINDOW w AS (PARTITION BY dept) ORDER BY RANK() OVER (ORDER BY salary DESC) ASC"
+	if got, _, err := q.Build(); err != nil {
+		t.Errorf("SelectQuery.Build() error = %v", err)
+	} else if got != want {
+		t.Errorf("SelectQuery.Build() got = %v, want %v", got, want)
+	}
+
+	invalid := []Builder{
+		Select(Over("RANK()", Window().PartitionBy("a; DROP TABLE t"))).From("t"),
+		Select(OverWindow("RANK()", "w x")).From("t"),
+		Select(Over("RANK()", Window().OrderBy("a", Asc)).As("r n")).From("t"),
+	}
+	for i, b := range invalid {
+		if _, _, err := b.Build(); err != ErrInvalidIdent {
+			t.Errorf("Build() %d error = %v, want %v", i, err, ErrInvalidIdent)
+		}
+	}
+}
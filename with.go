@@ -0,0 +1,148 @@
+package qb
+
+import (
+	"fmt"
+	"strings"
+)
+
+type cte struct {
+	name         string
+	cols         []string
+	query        Builder
+	materialized *bool
+}
+
+// WithClause represents a `WITH` clause containing one or more common table
+// expressions. A WithClause is created with With and is turned into a query
+// with Select, InsertInto, Update, or DeleteFrom.
+type WithClause struct {
+	recursive bool
+	ctes      []cte
+}
+
+// With starts a `WITH` clause with a common table expression named name and
+// defined by query.
+func With(name string, query Builder) *WithClause {
+	return (&WithClause{}).With(name, query)
+}
+
+// With adds another common table expression to the clause.
+func (w *WithClause) With(name string, query Builder) *WithClause {
+	w.ctes = append(w.ctes, cte{name: name, query: query})
+	return w
+}
+
+// Columns sets the column list of the most recently added common table
+// expression.
+func (w *WithClause) Columns(cols ...string) *WithClause {
+	if len(w.ctes) > 0 {
+		c := &w.ctes[len(w.ctes)-1]
+		c.cols = append(c.cols, cols...)
+	}
+	return w
+}
+
+// Materialized adds the `MATERIALIZED` hint to the most recently added common
+// table expression.
+func (w *WithClause) Materialized() *WithClause { return w.setMaterialized(true) }
+
+// NotMaterialized adds the `NOT MATERIALIZED` hint to the most recently added
+// common table expression.
+func (w *WithClause) NotMaterialized() *WithClause { return w.setMaterialized(false) }
+
+func (w *WithClause) setMaterialized(m bool) *WithClause {
+	if len(w.ctes) > 0 {
+		w.ctes[len(w.ctes)-1].materialized = &m
+	}
+	return w
+}
+
+// Recursive turns the clause into a `WITH RECURSIVE` clause.
+func (w *WithClause) Recursive() *WithClause {
+	w.recursive = true
+	return w
+}
+
+// Select starts a select query prefixed with the clause.
+func (w *WithClause) Select(cols ...interface{}) *SelectQuery {
+	q := Select(cols...)
+	q.with = w
+	return q
+}
+
+// InsertInto starts an insert query prefixed with the clause.
+func (w *WithClause) InsertInto(table string) *InsertQuery {
+	q := InsertInto(table)
+	q.with = w
+	return q
+}
+
+// Update starts an update query prefixed with the clause.
+func (w *WithClause) Update(table string) *UpdateQuery {
+	q := Update(table)
+	q.with = w
+	return q
+}
+
+// DeleteFrom starts a delete query prefixed with the clause.
+func (w *WithClause) DeleteFrom(table interface{}) *DeleteQuery {
+	q := DeleteFrom(table)
+	q.with = w
+	return q
+}
+
+// Build builds the clause using the default dialect.
+func (w *WithClause) Build() (string, []interface{}, error) { return w.buildDialect(defaultDialect()) }
+
+func (w *WithClause) buildDialect(d Dialect) (string, []interface{}, error) {
+	var sb strings.Builder
+	var params []interface{}
+
+	sb.WriteString("WITH ")
+	if w.recursive && d.Supports(FeatureRecursiveKeyword) {
+		sb.WriteString("RECURSIVE ")
+	}
+
+	for i, c := range w.ctes {
+		if err := checkIdents(c.name); err != nil {
+			return "", nil, err
+		} else if err := checkIdents(c.cols...); err != nil {
+			return "", nil, err
+		} else if isNil(c.query) {
+			return "", nil, ErrInvalidType
+		}
+
+		switch c.query.(type) {
+		case *InsertQuery, *UpdateQuery, *DeleteQuery:
+			if !d.Supports(FeatureDataModifyingCTE) {
+				return "", nil, ErrUnsupported
+			}
+		}
+
+		q, p, err := buildWith(c.query, d)
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, p...)
+
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(c.name)
+		if len(c.cols) > 0 {
+			fmt.Fprintf(&sb, " (%s)", strings.Join(c.cols, ", "))
+		}
+		sb.WriteString(" AS ")
+		if c.materialized != nil {
+			if !d.Supports(FeatureMaterializedCTE) {
+				return "", nil, ErrUnsupported
+			} else if !*c.materialized {
+				sb.WriteString("NOT ")
+			}
+			sb.WriteString("MATERIALIZED ")
+		}
+		fmt.Fprintf(&sb, "(%s)", q)
+	}
+
+	return sb.String(), params, nil
+}
@@ -0,0 +1,113 @@
+package qb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithClause_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   Builder
+		want    string
+		want1   []interface{}
+		wantErr bool
+	}{
+		{
+			name: "Select with CTE",
+			query: With("recent", Select("id").From("orders").Where(Gt("created_at", "2020-01-01"))).
+				Select("*").From("recent").Where(Eq("id", 5)),
+			want:    "WITH recent AS (SELECT id FROM orders WHERE created_at>?) SELECT * FROM recent WHERE id=?",
+			want1:   []interface{}{"2020-01-01", 5},
+			wantErr: false,
+		},
+		{
+			name: "Multiple CTEs with columns and hints",
+			query: With("a", Select("x").From("t1").Where(Eq("y", 1))).Columns("x").Materialized().
+				With("b", Select("x").From("t2").Where(Eq("y", 2))).NotMaterialized().
+				Select().From("a").WithDialect(Postgres),
+			want:    "WITH a (x) AS MATERIALIZED (SELECT x FROM t1 WHERE y=$1), b AS NOT MATERIALIZED (SELECT x FROM t2 WHERE y=$2) SELECT * FROM a",
+			want1:   []interface{}{1, 2},
+			wantErr: false,
+		},
+		{
+			name:    "Recursive",
+			query:   With("nums", S("SELECT 1 UNION ALL SELECT n+1 FROM nums WHERE n < 10")).Columns("n").Recursive().Select("n").From("nums"),
+			want:    "WITH RECURSIVE nums (n) AS (SELECT 1 UNION ALL SELECT n+1 FROM nums WHERE n < 10) SELECT n FROM nums",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "Recursive without keyword",
+			query:   With("nums", S("SELECT 1")).Recursive().Select("n").From("nums").WithDialect(SQLServer),
+			want:    "WITH nums AS (SELECT 1) SELECT n FROM nums",
+			want1:   nil,
+			wantErr: false,
+		},
+		{
+			name: "Data modifying CTE",
+			query: With("moved", DeleteFrom("products").Where(Eq("discontinued", true)).Returning("*")).
+				InsertInto("archived_products").FromSelect(Select().From("moved")).WithDialect(Postgres),
+			want:    `WITH moved AS (DELETE FROM products WHERE discontinued=$1 RETURNING *) INSERT INTO "archived_products" SELECT * FROM moved`,
+			want1:   []interface{}{true},
+			wantErr: false,
+		},
+		{
+			name:    "Update with CTE",
+			query:   With("ids", Select("id").From("t").Where(Eq("a", 1))).Update("t").Set("b", 2).Where(Pred{"id", " in ", S("SELECT id FROM ids")}),
+			want:    `WITH ids AS (SELECT id FROM t WHERE a=?) UPDATE "t" SET b=? WHERE id in (SELECT id FROM ids)`,
+			want1:   []interface{}{1, 2},
+			wantErr: false,
+		},
+		{
+			name:    "Delete with CTE",
+			query:   With("ids", Select("id").From("t").Where(Eq("a", 1))).DeleteFrom("t").Where(Eq("b", 2)),
+			want:    "WITH ids AS (SELECT id FROM t WHERE a=?) DELETE FROM t WHERE b=?",
+			want1:   []interface{}{1, 2},
+			wantErr: false,
+		},
+		{
+			name:    "Data modifying CTE unsupported",
+			query:   With("moved", DeleteFrom("products").Returning("*")).Select().From("moved").WithDialect(SQLite),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Materialized unsupported",
+			query:   With("a", Select().From("t")).Materialized().Select().From("a").WithDialect(MySQL),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Nil query",
+			query:   With("x", (*SelectQuery)(nil)).Select().From("x"),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "Nil data modifying query",
+			query:   With("x", (*DeleteQuery)(nil)).Select().From("x"),
+			want:    "",
+			want1:   nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1, err := tt.query.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WithClause.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("WithClause.Build() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("WithClause.Build() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}